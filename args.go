@@ -0,0 +1,81 @@
+package gormzap
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WithArgsBytes returns Logger option that logs an estimated size of bind
+// values of the query in bytes as "sql.args_bytes" field, so that unusually
+// large writes, e.g. of giant JSON blobs, are easy to spot and alert on.
+// See Record.ArgsBytes.
+func WithArgsBytes() LoggerOption {
+	return func(l *Logger) {
+		l.argsBytes = true
+	}
+}
+
+// WithArgsCount returns Logger option that logs the number of bind values
+// of the query as "sql.args_count" field, since very large IN lists or bulk
+// inserts correlate strongly with slow queries and planner issues.
+//
+// ParameterizedRecordToFields always logs this field, so the option is meant
+// for other encoders, e.g. DefaultRecordToFields.
+func WithArgsCount() LoggerOption {
+	return func(l *Logger) {
+		l.argsCount = true
+	}
+}
+
+// ArgsBytes returns an estimated size of the bind values in bytes: the length
+// of strings and byte slices, the size of numbers, and the length of other
+// values formatted as text.
+func (r Record) ArgsBytes() int {
+	n := 0
+	for _, v := range r.Args {
+		n += argSize(v)
+	}
+	return n
+}
+
+func argSize(value interface{}) int {
+	indirectValue := reflect.Indirect(reflect.ValueOf(value))
+	if !indirectValue.IsValid() {
+		return 0
+	}
+
+	switch v := indirectValue.Interface().(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case int, int64, uint, uint64, float64, time.Time:
+		return 8
+	case sql.NamedArg:
+		return argSize(v.Value)
+	case driver.Valuer:
+		dv, err := driverValue(v)
+		if err != nil {
+			return 0
+		}
+		return argSize(dv)
+	default:
+		return len(fmt.Sprintf("%v", v))
+	}
+}
+
+// argsBytesField returns "sql.args_bytes" field of the record.
+func argsBytesField(r Record) zap.Field {
+	return zap.Int("sql.args_bytes", r.ArgsBytes())
+}
@@ -0,0 +1,32 @@
+package gormzap_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithArgsBytes(t *testing.T) {
+	l, buf := logger(gormzap.WithArgsBytes())
+
+	args := []interface{}{"foo", []byte(`{"a":1}`), int64(1), true, nil, sql.Named("id", int32(7))}
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "INSERT INTO t VALUES (?, ?, ?, ?, ?, ?)", args, int64(1))
+
+	expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"INSERT INTO t VALUES ('foo', '{\"a\":1}', 1, 'true', NULL, 7)","sql.rows_affected":1,"sql.args_bytes":23}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithArgsCount(t *testing.T) {
+	l, buf := logger(gormzap.WithArgsCount())
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM t WHERE id IN (?, ?, ?)", []interface{}{1, 2, 3}, int64(3))
+
+	expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM t WHERE id IN (1, 2, 3)","sql.rows_affected":3,"sql.args_count":3}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
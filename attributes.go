@@ -0,0 +1,140 @@
+package gormzap
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DBAttributes are static attributes of the database the logger is used
+// for, following OpenTelemetry semantic conventions.
+type DBAttributes struct {
+	// System is a database management system, e.g. "postgresql" or "mysql".
+	System string
+	// Name is a database name.
+	Name string
+	// ServerAddress is a database server host name or IP address.
+	ServerAddress string
+	// ServerPort is a database server port.
+	ServerPort int
+}
+
+// WithDBAttributes returns Logger option that adds non-empty attributes to
+// every record as "db.system", "db.name", "server.address" and "server.port"
+// fields, matching what OpenTelemetry collector pipelines expect for
+// database telemetry. The fields are not affected by WithFieldPrefix.
+func WithDBAttributes(a DBAttributes) LoggerOption {
+	return func(l *Logger) {
+		if a.System != "" {
+			l.setStaticField(zap.String("db.system", a.System))
+		}
+		if a.Name != "" {
+			l.setStaticField(zap.String("db.name", a.Name))
+		}
+		if a.ServerAddress != "" {
+			l.setStaticField(zap.String("server.address", a.ServerAddress))
+		}
+		if a.ServerPort != 0 {
+			l.setStaticField(zap.Int("server.port", a.ServerPort))
+		}
+	}
+}
+
+// WithDatabaseInfo returns Logger option that adds non-empty identity of the
+// database to every record as "sql.database", "sql.host" and "sql.user"
+// fields, so that records of services talking to multiple databases can be
+// told apart.
+func WithDatabaseInfo(name, host, user string) LoggerOption {
+	return func(l *Logger) {
+		if name != "" {
+			l.setStaticField(zap.String("sql.database", name))
+		}
+		if host != "" {
+			l.setStaticField(zap.String("sql.host", host))
+		}
+		if user != "" {
+			l.setStaticField(zap.String("sql.user", user))
+		}
+	}
+}
+
+// WithRole returns Logger option that sets role of the database connection
+// the logger is used for, e.g. "primary" or "read-replica-1". The role is
+// added to every record as "sql.role" field and set as Record.Role, so that
+// read/write split setups can tell which connection produced each record and
+// compare replica latency.
+func WithRole(role string) LoggerOption {
+	return func(l *Logger) {
+		l.role = role
+		l.setStaticField(zap.String("sql.role", role))
+	}
+}
+
+// WithServiceInfo returns Logger option that adds host name and process ID,
+// and non-empty service name and version to every record as "host.name",
+// "process.pid", "service.name" and "service.version" fields, following
+// OpenTelemetry semantic conventions, so that records of many instances are
+// attributable without extra zap wiring. The fields are not affected by
+// WithFieldPrefix.
+func WithServiceInfo(name, version string) LoggerOption {
+	return func(l *Logger) {
+		if host, err := os.Hostname(); err == nil {
+			l.setStaticField(zap.String("host.name", host))
+		}
+		l.setStaticField(zap.Int("process.pid", os.Getpid()))
+		if name != "" {
+			l.setStaticField(zap.String("service.name", name))
+		}
+		if version != "" {
+			l.setStaticField(zap.String("service.version", version))
+		}
+	}
+}
+
+// WithKubernetesInfo returns Logger option that adds pod name, namespace and
+// node name to every record as "k8s.pod.name", "k8s.namespace.name" and
+// "k8s.node.name" fields, following OpenTelemetry semantic conventions. The
+// values are read from POD_NAME, POD_NAMESPACE and NODE_NAME environment
+// variables, which are conventionally set with the downward API:
+//  env:
+//    - name: POD_NAME
+//      valueFrom:
+//        fieldRef:
+//          fieldPath: metadata.name
+//    - name: POD_NAMESPACE
+//      valueFrom:
+//        fieldRef:
+//          fieldPath: metadata.namespace
+//    - name: NODE_NAME
+//      valueFrom:
+//        fieldRef:
+//          fieldPath: spec.nodeName
+//
+// Fields of unset variables are omitted.
+func WithKubernetesInfo() LoggerOption {
+	return func(l *Logger) {
+		if v := os.Getenv("POD_NAME"); v != "" {
+			l.setStaticField(zap.String("k8s.pod.name", v))
+		}
+		if v := os.Getenv("POD_NAMESPACE"); v != "" {
+			l.setStaticField(zap.String("k8s.namespace.name", v))
+		}
+		if v := os.Getenv("NODE_NAME"); v != "" {
+			l.setStaticField(zap.String("k8s.node.name", v))
+		}
+	}
+}
+
+// setStaticField adds f to the fields of every record, replacing the field
+// with the same key, e.g. set by the options of the logger CloneWith is
+// called on.
+func (l *Logger) setStaticField(f zapcore.Field) {
+	for i := range l.staticFields {
+		if l.staticFields[i].Key == f.Key {
+			l.staticFields[i] = f
+			return
+		}
+	}
+	l.staticFields = append(l.staticFields, f)
+}
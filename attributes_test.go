@@ -0,0 +1,118 @@
+package gormzap_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithDBAttributes(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithFieldPrefix("db."),
+		gormzap.WithDBAttributes(gormzap.DBAttributes{
+			System:        "postgresql",
+			Name:          "app",
+			ServerAddress: "db.example.com",
+			ServerPort:    5432,
+		}),
+	)
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"level":"error","msg":"some serious error!","db.source":"/some/file.go:32","db.system":"postgresql","db.name":"app","server.address":"db.example.com","server.port":5432}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithDatabaseInfo(t *testing.T) {
+	l, buf := logger(gormzap.WithDatabaseInfo("app", "db.example.com", ""))
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","sql.database":"app","sql.host":"db.example.com"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithRole(t *testing.T) {
+	l, buf := logger(gormzap.WithRole("read-replica-1"))
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","sql.role":"read-replica-1"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+
+	l, records := observer(gormzap.WithRole("primary"))
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	if role := records.AllRecords()[0].Role; role != "primary" {
+		t.Fatalf("Expected record role primary but got %q", role)
+	}
+}
+
+func TestWithRole_cloneWith(t *testing.T) {
+	l, buf := logger(gormzap.WithRole("primary"), gormzap.WithDatabaseInfo("main", "db-1", ""))
+	clone := l.CloneWith(gormzap.WithRole("replica"), gormzap.WithDatabaseInfo("main", "db-2", ""))
+
+	clone.Print("/some/file.go:32", errors.New("some serious error!"))
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := []string{
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","sql.role":"replica","sql.database":"main","sql.host":"db-2"}`,
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","sql.role":"primary","sql.database":"main","sql.host":"db-1"}`,
+	}
+
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
+
+func TestWithServiceInfo(t *testing.T) {
+	l, buf := logger(gormzap.WithServiceInfo("app", "1.2.3"))
+
+	host, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := fmt.Sprintf(`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","host.name":%q,"process.pid":%d,"service.name":"app","service.version":"1.2.3"}`, host, os.Getpid())
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithKubernetesInfo(t *testing.T) {
+	env := map[string]string{
+		"POD_NAME":      "app-7d4b9c8f6-x2x8q",
+		"POD_NAMESPACE": "default",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+	os.Unsetenv("NODE_NAME")
+
+	l, buf := logger(gormzap.WithKubernetesInfo())
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","k8s.pod.name":"app-7d4b9c8f6-x2x8q","k8s.namespace.name":"default"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
@@ -0,0 +1,59 @@
+package gormzap
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// cancelMessages are messages of errors returned by drivers when a query is
+// cancelled, which do not wrap context errors.
+var cancelMessages = []string{
+	context.Canceled.Error(),
+	context.DeadlineExceeded.Error(),
+	// lib/pq cancels the query on the server side when its context is done.
+	"canceling statement due to user request",
+}
+
+// isCancelled reports whether err is caused by cancellation of the query
+// context rather than by a genuine failure.
+func isCancelled(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Errors may be wrapped without %w by drivers or joined by gorm.
+	msg := errorString(err)
+	for _, m := range cancelMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCancelledLevel returns Logger option that logs errors caused by
+// cancellation of the query context with at most the given level. By default
+// such errors are logged with warn level, so that client disconnects do not
+// pollute error dashboards; error level logs them like any other error.
+func WithCancelledLevel(level zapcore.Level) LoggerOption {
+	return func(l *Logger) {
+		l.cancelledLevel = level
+	}
+}
+
+// classifyCancellation marks the record as cancelled if its error is caused
+// by cancellation of the query context, and lowers its level to the one set
+// by WithCancelledLevel.
+func (l *Logger) classifyCancellation(rec *Record) {
+	if rec.Err == nil || !isCancelled(rec.Err) {
+		return
+	}
+
+	rec.Cancelled = true
+	if rec.Level > l.cancelledLevel {
+		rec.Level = l.cancelledLevel
+	}
+}
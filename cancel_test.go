@@ -0,0 +1,62 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestLogger_Print_cancelled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []gormzap.LoggerOption
+		err      error
+		expected string
+	}{
+		{
+			name:     "canceled",
+			err:      context.Canceled,
+			expected: `{"level":"warn","msg":"context canceled","sql.source":"/some/file.go:32","sql.cancelled":true}`,
+		},
+		{
+			name:     "deadline exceeded",
+			opts:     []gormzap.LoggerOption{gormzap.WithCancelledLevel(zap.WarnLevel)},
+			err:      fmt.Errorf("query: %w", context.DeadlineExceeded),
+			expected: `{"level":"warn","msg":"query: context deadline exceeded","sql.source":"/some/file.go:32","sql.cancelled":true}`,
+		},
+		{
+			name:     "driver",
+			opts:     []gormzap.LoggerOption{gormzap.WithCancelledLevel(zap.WarnLevel)},
+			err:      errors.New("pq: canceling statement due to user request"),
+			expected: `{"level":"warn","msg":"pq: canceling statement due to user request","sql.source":"/some/file.go:32","sql.cancelled":true}`,
+		},
+		{
+			name:     "error level",
+			opts:     []gormzap.LoggerOption{gormzap.WithCancelledLevel(zap.ErrorLevel)},
+			err:      context.Canceled,
+			expected: `{"level":"error","msg":"context canceled","sql.source":"/some/file.go:32","sql.cancelled":true}`,
+		},
+		{
+			name:     "failure",
+			opts:     []gormzap.LoggerOption{gormzap.WithCancelledLevel(zap.WarnLevel)},
+			err:      errors.New("some serious error!"),
+			expected: `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, buf := logger(tc.opts...)
+
+			l.Print("/some/file.go:32", tc.err)
+
+			if actual := buf.Lines()[0]; actual != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
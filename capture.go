@@ -0,0 +1,190 @@
+package gormzap
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CaptureSink is RecordSink that captures executed statements for later
+// load replay or regression analysis against a staging database. Statements
+// are written with placeholders, each preceded by a comment with its number:
+//  -- 1
+//  SELECT * FROM users WHERE id = $1;
+// and their bind values are written to a sidecar stream of JSON lines with
+// the same numbers:
+//  {"n":1,"time":"2006-01-02T15:04:05Z","duration":0.005,"args":[42]}
+//
+// Time is the query start time, and duration is in seconds. Values
+// implementing driver.Valuer are captured as their driver values, and byte
+// slices are captured as strings if they are valid UTF-8, or base64 encoded
+// otherwise. Non-finite floats are captured as "NaN", "+Inf" and "-Inf"
+// strings. Records with values that still cannot be marshaled are skipped.
+//
+// The first write error stops the sink, and is returned by Err.
+type CaptureSink struct {
+	mu         sync.Mutex
+	statements io.Writer
+	args       io.Writer
+	closers    []io.Closer
+	n          int64
+	err        error
+}
+
+// NewCaptureSink returns a new CaptureSink writing statements and their bind
+// values to the given writers.
+func NewCaptureSink(statements, args io.Writer) *CaptureSink {
+	return &CaptureSink{
+		statements: statements,
+		args:       args,
+	}
+}
+
+// OpenCapture returns a new CaptureSink writing statements to the file at
+// path, and their bind values to the file at path with ".args.json" suffix.
+// Existing files are truncated. The sink must be closed with Close.
+func OpenCapture(path string) (*CaptureSink, error) {
+	statements, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	args, err := os.Create(path + ".args.json")
+	if err != nil {
+		statements.Close()
+		return nil, err
+	}
+
+	s := NewCaptureSink(statements, args)
+	s.closers = []io.Closer{statements, args}
+	return s, nil
+}
+
+type capturedArgs struct {
+	N        int64         `json:"n"`
+	Time     time.Time     `json:"time"`
+	Duration float64       `json:"duration"`
+	Args     []interface{} `json:"args"`
+}
+
+// WriteRecord implements RecordSink. Only records of SQL queries are
+// captured.
+func (s *CaptureSink) WriteRecord(r Record) {
+	if r.SQL == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+
+	args, err := json.Marshal(capturedArgs{
+		N:        s.n + 1,
+		Time:     r.Start,
+		Duration: r.Duration.Seconds(),
+		Args:     captureArgs(r.Args),
+	})
+	if err != nil {
+		// A value that cannot be captured is not a write error, so the
+		// record is skipped without stopping the sink.
+		return
+	}
+	s.n++
+
+	if _, s.err = io.WriteString(s.statements, "-- "+strconv.FormatInt(s.n, 10)+"\n"+r.Statement+";\n"); s.err != nil {
+		return
+	}
+	_, s.err = s.args.Write(append(args, '\n'))
+}
+
+// Err returns the first write error of the sink, if any.
+func (s *CaptureSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Close closes files opened by OpenCapture. It returns the first write or
+// close error.
+func (s *CaptureSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.closers {
+		if err := c.Close(); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+	s.closers = nil
+	return s.err
+}
+
+// captureArgs returns bind values that can be marshaled to JSON.
+func captureArgs(args []interface{}) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, v := range args {
+		values[i] = captureArg(v)
+	}
+	return values
+}
+
+func captureArg(v interface{}) interface{} {
+	switch v := v.(type) {
+	case sql.NamedArg:
+		return captureArg(v.Value)
+	case driver.Valuer:
+		dv, err := driverValue(v)
+		if err != nil {
+			return nil
+		}
+		return captureArg(dv)
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return v
+	case float32:
+		return captureFloat(float64(v), v)
+	case float64:
+		return captureFloat(v, v)
+	case time.Time:
+		if y := v.Year(); y < 0 || y > 9999 {
+			// Such times cannot be marshaled to RFC 3339.
+			return v.String()
+		}
+		return v
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return v
+	default:
+		if _, err := json.Marshal(v); err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return v
+	}
+}
+
+// captureFloat returns v as is, or f as string if it is not finite, since JSON
+// has no representation of NaN and infinities.
+func captureFloat(f float64, v interface{}) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+package gormzap_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestCaptureSink(t *testing.T) {
+	var statements, args bytes.Buffer
+	sink := gormzap.NewCaptureSink(&statements, &args)
+
+	l, _ := logger(gormzap.WithSinks(sink))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users WHERE id = $1", []interface{}{42}, int64(1))
+	l.Print("/some/file.go:35", "some message")
+	l.Print("sql", "/some/file.go:36", time.Millisecond*250, "UPDATE users SET name = ?, data = ? WHERE id = ?", []interface{}{"foo", []byte{0xff}, nil}, int64(1))
+
+	if err := sink.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "-- 1\nSELECT * FROM users WHERE id = $1;\n-- 2\nUPDATE users SET name = ?, data = ? WHERE id = ?;\n"
+	if actual := statements.String(); actual != expected {
+		t.Fatalf("Expected %q but got %q", expected, actual)
+	}
+
+	lines := strings.Split(strings.TrimSpace(args.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines but got %d", len(lines))
+	}
+	for i, suffix := range []string{`"duration":0.005,"args":[42]}`, `"duration":0.25,"args":["foo","/w==",null]}`} {
+		if !strings.HasSuffix(lines[i], suffix) {
+			t.Fatalf("Expected line to end with %s but got %s", suffix, lines[i])
+		}
+	}
+	if !strings.HasPrefix(lines[1], `{"n":2,"time":"`) {
+		t.Fatalf("Expected line to start with number and time but got %s", lines[1])
+	}
+}
+
+func TestCaptureSink_nonFiniteFloats(t *testing.T) {
+	var statements, args bytes.Buffer
+	sink := gormzap.NewCaptureSink(&statements, &args)
+
+	l, _ := logger(gormzap.WithSinks(sink))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "INSERT INTO t (a, b, c) VALUES (?, ?, ?)", []interface{}{math.NaN(), math.Inf(1), float32(math.Inf(-1))}, int64(1))
+	l.Print("sql", "/some/file.go:35", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+	if err := sink.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(args.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines but got %d", len(lines))
+	}
+	if suffix := `"args":["NaN","+Inf","-Inf"]}`; !strings.HasSuffix(lines[0], suffix) {
+		t.Fatalf("Expected line to end with %s but got %s", suffix, lines[0])
+	}
+}
+
+func TestOpenCapture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gormzap")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "capture.sql")
+	sink, err := gormzap.OpenCapture(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l, _ := logger(gormzap.WithSinks(sink))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(b) != "-- 1\nSELECT 1;\n" {
+		t.Fatalf("Unexpected statements: %q", b)
+	}
+
+	b, err = ioutil.ReadFile(path + ".args.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(string(b), `"args":[]}`+"\n") {
+		t.Fatalf("Unexpected args: %q", b)
+	}
+}
@@ -0,0 +1,105 @@
+package gormzap
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxGuardedKeys is the maximum number of distinct field keys tracked by the
+// cardinality guard. Values of keys beyond it are always bucketed.
+const maxGuardedKeys = 1024
+
+var uuidRegexp = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+// WithCardinalityGuard returns Logger option that protects index-based log
+// backends from values with exploding cardinality. UUIDs in messages of
+// non-query records, e.g. errors, are replaced with "<uuid>", so that
+// messages of the same kind are identical. String values of tags and
+// additional fields, e.g. added by rules, are tracked by key: once a key has
+// max distinct values, other values of it are replaced with one of max
+// buckets, chosen by value hash, e.g. "bucket:7".
+//
+// If max is zero or negative, the guard is disabled.
+func WithCardinalityGuard(max int) LoggerOption {
+	return func(l *Logger) {
+		if max <= 0 {
+			l.cardinalityGuard = nil
+			return
+		}
+		l.cardinalityGuard = &cardinalityGuard{max: max}
+	}
+}
+
+// cardinalityGuard tracks distinct values of field keys. Like boundedSet,
+// it counts keys with atomics instead of taking a lock.
+type cardinalityGuard struct {
+	keys int64
+	max  int
+
+	// values holds *boundedSet of values by key.
+	values sync.Map
+}
+
+// value returns the value if it is one of the first max distinct values of
+// the key, or its bucket otherwise.
+func (g *cardinalityGuard) value(key, value string) string {
+	if values := g.keyValues(key); values != nil && values.add(value) {
+		return value
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(value))
+	return "bucket:" + strconv.Itoa(int(h.Sum32()%uint32(g.max)))
+}
+
+// keyValues returns the set of values of the key, or nil if the key is not
+// tracked and the guard already tracks maxGuardedKeys keys.
+func (g *cardinalityGuard) keyValues(key string) *boundedSet {
+	if v, ok := g.values.Load(key); ok {
+		return v.(*boundedSet)
+	}
+	if atomic.AddInt64(&g.keys, 1) > maxGuardedKeys {
+		atomic.AddInt64(&g.keys, -1)
+		if v, ok := g.values.Load(key); ok {
+			return v.(*boundedSet)
+		}
+		return nil
+	}
+	v, loaded := g.values.LoadOrStore(key, newBoundedSet(g.max))
+	if loaded {
+		atomic.AddInt64(&g.keys, -1)
+	}
+	return v.(*boundedSet)
+}
+
+// guard replaces high cardinality values of the record.
+func (g *cardinalityGuard) guard(rec *Record) {
+	if rec.SQL == "" {
+		rec.Message = uuidRegexp.ReplaceAllString(rec.Message, "<uuid>")
+	}
+
+	if len(rec.Tags) > 0 {
+		tags := make(Tags, len(rec.Tags))
+		for i, tag := range rec.Tags {
+			tags[i] = Tag{Key: tag.Key, Value: g.value("sql.tags."+tag.Key, tag.Value)}
+		}
+		rec.Tags = tags
+	}
+
+	if len(rec.Fields) > 0 {
+		fields := make([]zapcore.Field, len(rec.Fields))
+		for i, f := range rec.Fields {
+			if f.Type == zapcore.StringType {
+				f = zap.String(f.Key, g.value(f.Key, f.String))
+			}
+			fields[i] = f
+		}
+		rec.Fields = fields
+	}
+}
@@ -0,0 +1,50 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestWithCardinalityGuard(t *testing.T) {
+	t.Run("message", func(t *testing.T) {
+		l, buf := logger(gormzap.WithCardinalityGuard(10))
+
+		l.Print("/some/file.go:32", errors.New("user 0b6cbd5e-8f2a-4cde-9d3b-5a1f1c2e7b90 not found"))
+
+		expected := `{"level":"error","msg":"user <uuid> not found","sql.source":"/some/file.go:32"}`
+		if actual := buf.Lines()[0]; actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("values", func(t *testing.T) {
+		l, buf := logger(
+			gormzap.WithCardinalityGuard(2),
+			gormzap.WithCommentTags(),
+			gormzap.WithRules(gormzap.RuleFunc(func(r gormzap.Record) []gormzap.Finding {
+				return []gormzap.Finding{{Fields: []zap.Field{zap.String("team", "billing")}}}
+			})),
+		)
+
+		for _, id := range []string{"a", "b", "c", "a"} {
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1 /* request="+id+" */", []interface{}{}, int64(1))
+		}
+
+		expected := []string{
+			`"sql.tags":{"request":"a"},"team":"billing"}`,
+			`"sql.tags":{"request":"b"},"team":"billing"}`,
+			`"sql.tags":{"request":"bucket:0"},"team":"billing"}`,
+			`"sql.tags":{"request":"a"},"team":"billing"}`,
+		}
+		lines := buf.Lines()
+		for i, e := range expected {
+			if actual := lines[i][len(lines[i])-len(e):]; actual != e {
+				t.Fatalf("Expected line to end with %s but got %s", e, lines[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Command gormzap-report reads JSON logs written by gormzap and prints
+// statistics of queries grouped by their normalized shape: count, average
+// and 95th percentile duration, and error rate. This helps to analyze query
+// behavior without a metrics stack.
+//
+// Usage:
+//  gormzap-report [flags] [file ...]
+//
+// Logs are read from the files, or from standard input if none are given.
+// Lines that are not JSON objects are skipped, so logs can be mixed with
+// other output.
+//
+// Errors are counted from error records carrying the failing statement, as
+// logged by gormzapplugin. Flags:
+//  -prefix string
+//      prefix of gormzap field keys (default "sql.")
+//  -duration-unit string
+//      unit of numeric durations: s, ms, us or ns (default "s")
+//  -sort string
+//      sort order: count, total, p95 or errors (default "count")
+//  -top int
+//      number of query shapes to print, or 0 for all (default 20)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.prefix, "prefix", "sql.", "prefix of gormzap field keys")
+	flag.StringVar(&cfg.durationUnit, "duration-unit", "s", "unit of numeric durations: s, ms, us or ns")
+	flag.StringVar(&cfg.sort, "sort", "count", "sort order: count, total, p95 or errors")
+	flag.IntVar(&cfg.top, "top", 20, "number of query shapes to print, or 0 for all")
+	flag.Parse()
+
+	if err := run(cfg, flag.Args(), os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "gormzap-report:", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg config, files []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	r, err := newReport(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		if err := r.read(stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = r.read(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if r.skipped > 0 {
+		fmt.Fprintf(stderr, "gormzap-report: skipped %d non-JSON lines\n", r.skipped)
+	}
+	return r.print(stdout)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// maxLineSize is the maximum size of a log line.
+const maxLineSize = 1 << 20
+
+// maxQueryLen is the maximum length of a printed query.
+const maxQueryLen = 80
+
+type config struct {
+	prefix       string
+	durationUnit string
+	sort         string
+	top          int
+}
+
+var durationUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+var errorLevels = map[string]bool{
+	"error":  true,
+	"dpanic": true,
+	"panic":  true,
+	"fatal":  true,
+}
+
+// report collects statistics of queries by their hash.
+type report struct {
+	cfg  config
+	unit time.Duration
+
+	shapes  map[string]*shape
+	skipped int
+}
+
+// shape holds statistics of queries of the same normalized shape.
+type shape struct {
+	hash      string
+	query     string
+	durations []time.Duration
+	total     time.Duration
+	errors    int
+}
+
+func newReport(cfg config) (*report, error) {
+	unit, ok := durationUnits[cfg.durationUnit]
+	if !ok {
+		return nil, fmt.Errorf("unknown duration unit %q", cfg.durationUnit)
+	}
+	switch cfg.sort {
+	case "count", "total", "p95", "errors":
+	default:
+		return nil, fmt.Errorf("unknown sort order %q", cfg.sort)
+	}
+
+	return &report{
+		cfg:    cfg,
+		unit:   unit,
+		shapes: make(map[string]*shape),
+	}, nil
+}
+
+// read reads log lines from r.
+func (r *report) read(rd io.Reader) error {
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 64*1024), maxLineSize)
+	for sc.Scan() {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &fields); err != nil {
+			r.skipped++
+			continue
+		}
+		r.add(fields)
+	}
+	return sc.Err()
+}
+
+// add adds a log record to the report. Records without query are ignored.
+func (r *report) add(fields map[string]interface{}) {
+	query := r.query(fields)
+	if query == "" {
+		return
+	}
+
+	hash := gormzap.QueryHash(query)
+	s, ok := r.shapes[hash]
+	if !ok {
+		s = &shape{hash: hash, query: gormzap.NormalizeQuery(query)}
+		r.shapes[hash] = s
+	}
+
+	d, ok := r.duration(fields[r.cfg.prefix+"duration"])
+	if !ok {
+		// Error records carry the failing statement, but no duration.
+		if level, _ := fields["level"].(string); errorLevels[level] {
+			s.errors++
+		}
+		return
+	}
+	s.durations = append(s.durations, d)
+	s.total += d
+}
+
+// query returns the query of the record, preferring forms with placeholders.
+func (r *report) query(fields map[string]interface{}) string {
+	for _, key := range []string{"statement", "template", "query"} {
+		if q, ok := fields[r.cfg.prefix+key].(string); ok && q != "" {
+			return q
+		}
+	}
+	return ""
+}
+
+// duration parses duration encoded by zap as a number in the configured
+// unit, or as a string, e.g. "5ms".
+func (r *report) duration(v interface{}) (time.Duration, bool) {
+	switch v := v.(type) {
+	case float64:
+		return time.Duration(v * float64(r.unit)), true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+// print prints the report as a table.
+func (r *report) print(w io.Writer) error {
+	shapes := make([]*shape, 0, len(r.shapes))
+	for _, s := range r.shapes {
+		shapes = append(shapes, s)
+	}
+	sort.Slice(shapes, func(i, j int) bool {
+		a, b := shapes[i], shapes[j]
+		switch r.cfg.sort {
+		case "total":
+			if a.total != b.total {
+				return a.total > b.total
+			}
+		case "p95":
+			if pa, pb := a.percentile(0.95), b.percentile(0.95); pa != pb {
+				return pa > pb
+			}
+		case "errors":
+			if a.errorRate() != b.errorRate() {
+				return a.errorRate() > b.errorRate()
+			}
+		}
+		if len(a.durations) != len(b.durations) {
+			return len(a.durations) > len(b.durations)
+		}
+		return a.hash < b.hash
+	})
+	if r.cfg.top > 0 && len(shapes) > r.cfg.top {
+		shapes = shapes[:r.cfg.top]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HASH\tCOUNT\tAVG\tP95\tERRORS\tQUERY")
+	for _, s := range shapes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.1f%%\t%s\n",
+			s.hash, len(s.durations), s.average(), s.percentile(0.95), s.errorRate()*100, truncate(s.query, maxQueryLen))
+	}
+	return tw.Flush()
+}
+
+func (s *shape) average() time.Duration {
+	if len(s.durations) == 0 {
+		return 0
+	}
+	return s.total / time.Duration(len(s.durations))
+}
+
+// percentile returns the nearest-rank percentile of durations.
+func (s *shape) percentile(p float64) time.Duration {
+	if len(s.durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), s.durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// errorRate returns the ratio of errors to queries.
+func (s *shape) errorRate() float64 {
+	if len(s.durations) == 0 {
+		if s.errors > 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(s.errors) / float64(len(s.durations))
+}
+
+// truncate cuts s to at most n bytes at rune boundary, marking it with "...".
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := n - 3
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + "..."
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	logs := strings.Join([]string{
+		`{"level":"info","msg":"gorm query","sql.source":"/app/users.go:10","sql.duration":0.005,"sql.query":"SELECT * FROM users WHERE id = 1","sql.rows_affected":1}`,
+		`{"level":"info","msg":"gorm query","sql.source":"/app/users.go:10","sql.duration":0.015,"sql.query":"SELECT * FROM users WHERE id = 2","sql.rows_affected":1}`,
+		`{"level":"error","msg":"pq: deadlock detected","sql.source":"/app/users.go:20","sql.statement":"UPDATE users SET name = $1 WHERE id = $2"}`,
+		`{"level":"info","msg":"gorm query","sql.source":"/app/users.go:20","sql.duration":0.1,"sql.query":"UPDATE users SET name = 'foo' WHERE id = 1","sql.rows_affected":0}`,
+		`not a JSON line`,
+		`{"level":"info","msg":"some message","sql.source":"/app/main.go:5"}`,
+	}, "\n")
+
+	var stdout, stderr bytes.Buffer
+	cfg := config{prefix: "sql.", durationUnit: "s", sort: "count", top: 20}
+	if err := run(cfg, nil, strings.NewReader(logs), &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines but got:\n%s", stdout.String())
+	}
+
+	expected := [][]string{
+		{"HASH", "COUNT", "AVG", "P95", "ERRORS", "QUERY"},
+		{"2", "10ms", "15ms", "0.0%", "select * from users where id = ?"},
+		{"1", "100ms", "100ms", "100.0%", "update users set name = ? where id = ?"},
+	}
+	for i, e := range expected {
+		actual := strings.Join(strings.Fields(lines[i]), " ")
+		if i > 0 {
+			// Skip the hash.
+			actual = actual[strings.IndexByte(actual, ' ')+1:]
+		}
+		if want := strings.Join(e, " "); actual != want {
+			t.Fatalf("Expected line %q but got %q", want, actual)
+		}
+	}
+
+	if expected := "gormzap-report: skipped 1 non-JSON lines\n"; stderr.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, stderr.String())
+	}
+}
+
+func TestRun_invalidConfig(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cfg := config{prefix: "sql.", durationUnit: "h", sort: "count"}
+	if err := run(cfg, nil, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("Expected error for unknown duration unit")
+	}
+}
@@ -0,0 +1,156 @@
+package gormzap
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config is a gorm logger configuration. It is an alternative to functional
+// options which can be loaded from application config. Zero value is a valid
+// configuration that logs queries without bind values with info level: note
+// that Interpolate is false unless set explicitly, unlike New which
+// interpolates values by default.
+//
+// Config can be decoded from JSON and YAML directly. Levels are represented as
+// zap level names ("debug", "info", etc.), and durations as Go duration
+// strings ("200ms"); JSON numbers are also accepted as nanoseconds.
+type Config struct {
+	// Level is a level for general logs, e.g. those that contain SQL queries.
+	Level zapcore.Level `json:"level" yaml:"level"`
+
+	// SlowThreshold marks queries taking longer as slow, see
+	// WithSlowThreshold. Zero disables slow query detection.
+	SlowThreshold time.Duration `json:"slow_threshold" yaml:"slow_threshold"`
+
+	// RowsAffectedWarning escalates write statements affecting more rows,
+	// see WithRowsAffectedWarning. Zero disables the check.
+	RowsAffectedWarning int64 `json:"rows_affected_warning" yaml:"rows_affected_warning"`
+
+	// Interpolate shows if bind values are interpolated into the logged
+	// query. When false, queries are logged with placeholders.
+	Interpolate bool `json:"interpolate" yaml:"interpolate"`
+
+	// MaxValueLen is a maximum length of an interpolated value, see
+	// WithMaxValueLen. Zero means default of 255, negative disables
+	// redaction.
+	MaxValueLen int `json:"max_value_len" yaml:"max_value_len"`
+
+	// FieldPrefix replaces "sql." prefix of the logged field keys, see
+	// WithFieldPrefix. Empty keeps the default.
+	FieldPrefix string `json:"field_prefix" yaml:"field_prefix"`
+
+	// QueryHash enables "sql.query_hash" field, see WithQueryHash.
+	QueryHash bool `json:"query_hash" yaml:"query_hash"`
+
+	// CommentTags enables parsing of query comments, see WithCommentTags.
+	CommentTags bool `json:"comment_tags" yaml:"comment_tags"`
+
+	// PrettySQL and Colors enable development formatting of queries, see
+	// WithPrettySQL and WithColors.
+	PrettySQL bool `json:"pretty_sql" yaml:"pretty_sql"`
+	Colors    bool `json:"colors" yaml:"colors"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type config Config
+	aux := struct {
+		*config
+		SlowThreshold duration `json:"slow_threshold"`
+	}{
+		config:        (*config)(c),
+		SlowThreshold: duration(c.SlowThreshold),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.SlowThreshold = time.Duration(aux.SlowThreshold)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Config) MarshalJSON() ([]byte, error) {
+	type config Config
+	return json.Marshal(struct {
+		config
+		SlowThreshold duration `json:"slow_threshold"`
+	}{
+		config:        config(c),
+		SlowThreshold: duration(c.SlowThreshold),
+	})
+}
+
+// duration is time.Duration represented as a string in JSON.
+type duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case float64:
+		*d = duration(v)
+		return nil
+	default:
+		return fmt.Errorf("gormzap: invalid duration %s", data)
+	}
+}
+
+// Options returns Logger options equivalent to the config.
+func (c Config) Options() []LoggerOption {
+	return []LoggerOption{c.apply}
+}
+
+// apply sets every logger setting covered by the config, so that it can be
+// used both for a new logger and to reconfigure an existing one.
+func (c Config) apply(l *Logger) {
+	l.level = c.Level
+	l.slowThreshold = c.SlowThreshold
+	l.rowsAffectedWarning = c.RowsAffectedWarning
+	l.withoutValues = !c.Interpolate
+	switch {
+	case c.MaxValueLen > 0:
+		l.maxValueLen = c.MaxValueLen
+	case c.MaxValueLen < 0:
+		l.maxValueLen = 0
+	default:
+		l.maxValueLen = maxLen
+	}
+	l.fieldPrefix = c.FieldPrefix
+	l.queryHash = c.QueryHash
+	l.commentTags = c.CommentTags
+	l.prettySQL = c.PrettySQL
+	l.colors = c.Colors
+}
+
+// NewFromConfig returns a new gorm logger configured with cfg. Additional
+// options are applied after the config.
+func NewFromConfig(origin *zap.Logger, cfg Config, opts ...LoggerOption) *Logger {
+	return New(origin, append(cfg.Options(), opts...)...)
+}
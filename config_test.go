@@ -0,0 +1,167 @@
+package gormzap_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	t.Run("zero config", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewFromConfig(z, gormzap.Config{})
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Second,
+			"SELECT * FROM test WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"info","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"1s","sql.query":"SELECT * FROM test WHERE id = $1","sql.rows_affected":1}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("full config", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewFromConfig(z, gormzap.Config{
+			SlowThreshold: time.Millisecond * 100,
+			Interpolate:   true,
+			MaxValueLen:   5,
+			FieldPrefix:   "db.",
+		})
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Second,
+			"SELECT * FROM test WHERE id = $1 AND name = $2",
+			[]interface{}{42, strings.Repeat("a", 10)},
+			int64(1),
+		)
+		expected := `{"level":"warn","msg":"gorm query","db.source":"/some/file.go:34","db.duration":"1s","db.query":"SELECT * FROM test WHERE id = 42 AND name = '<redacted>'","db.rows_affected":1,"db.slow":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		json     string
+		expected gormzap.Config
+	}{
+		{
+			json: `{"level":"warn","slow_threshold":"150ms","interpolate":true,"field_prefix":"db."}`,
+			expected: gormzap.Config{
+				Level:         zap.WarnLevel,
+				SlowThreshold: time.Millisecond * 150,
+				Interpolate:   true,
+				FieldPrefix:   "db.",
+			},
+		},
+		{
+			json: `{"slow_threshold":1000000}`,
+			expected: gormzap.Config{
+				SlowThreshold: time.Millisecond,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.json, func(t *testing.T) {
+			var cfg gormzap.Config
+			if err := json.Unmarshal([]byte(tc.json), &cfg); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if cfg != tc.expected {
+				t.Fatalf("Expected %+v but got %+v", tc.expected, cfg)
+			}
+		})
+	}
+
+	t.Run("invalid duration", func(t *testing.T) {
+		var cfg gormzap.Config
+		if err := json.Unmarshal([]byte(`{"slow_threshold":"soon"}`), &cfg); err == nil {
+			t.Fatalf("Expected error but got nil")
+		}
+	})
+}
+
+func TestConfig_MarshalJSON(t *testing.T) {
+	cfg := gormzap.Config{
+		Level:         zap.WarnLevel,
+		SlowThreshold: time.Millisecond * 150,
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var actual gormzap.Config
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != cfg {
+		t.Fatalf("Expected %+v but got %+v", cfg, actual)
+	}
+
+	if !strings.Contains(string(data), `"slow_threshold":"150ms"`) {
+		t.Fatalf("Expected duration as string in %s", data)
+	}
+}
+
+func TestConfig_yaml(t *testing.T) {
+	doc := `
+level: warn
+slow_threshold: 150ms
+interpolate: true
+max_value_len: 32
+field_prefix: db.
+`
+	expected := gormzap.Config{
+		Level:         zap.WarnLevel,
+		SlowThreshold: time.Millisecond * 150,
+		Interpolate:   true,
+		MaxValueLen:   32,
+		FieldPrefix:   "db.",
+	}
+
+	var cfg gormzap.Config
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg != expected {
+		t.Fatalf("Expected %+v but got %+v", expected, cfg)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "level: warn\n") || !strings.Contains(string(data), "slow_threshold: 150ms\n") {
+		t.Fatalf("Expected level and duration as strings in %s", data)
+	}
+
+	var actual gormzap.Config
+	if err := yaml.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != cfg {
+		t.Fatalf("Expected %+v but got %+v", cfg, actual)
+	}
+}
@@ -0,0 +1,150 @@
+package gormzap
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ContextLogger is a gorm logger bound to a context, e.g. of an HTTP request.
+// It logs records the same way as Logger, but also numbers queries made
+// within the context with "sql.seq" field, and adds total time spent in the
+// database within the context so far as "sql.db_time" field. If the context
+// has a deadline, time remaining until it when the query completed is logged
+// as "ctx.deadline_remaining" field, which is negative if the deadline has
+// passed.
+//
+// Example usage:
+//  ctx = gormzap.NewContext(ctx)
+//  db := orm.New()
+//  db.SetLogger(log.WithContext(ctx))
+type ContextLogger struct {
+	logger *Logger
+	ctx    context.Context
+	stats  *contextStats
+}
+
+// contextStats holds statistics of queries made within a context.
+type contextStats struct {
+	queries  int64
+	duration int64
+
+	budget Budget
+}
+
+// Budget limits queries made within a context. Once a limit is exceeded,
+// records of the following queries are logged with at least warn level and
+// "sql.budget_exceeded" field.
+type Budget struct {
+	// MaxQueries is the maximum number of queries, or zero for no limit.
+	MaxQueries int64
+	// MaxDBTime is the maximum total time spent in the database, or zero
+	// for no limit.
+	MaxDBTime time.Duration
+}
+
+// exceeded reports whether the stats exceed the budget.
+func (b Budget) exceeded(queries int64, dbTime time.Duration) bool {
+	return (b.MaxQueries > 0 && queries > b.MaxQueries) ||
+		(b.MaxDBTime > 0 && dbTime > b.MaxDBTime)
+}
+
+type contextStatsKey struct{}
+
+// NewContext returns a copy of parent which carries query statistics. All
+// loggers bound to the returned context or its children with WithContext
+// share them, so that queries are numbered across database handles.
+func NewContext(parent context.Context) context.Context {
+	return context.WithValue(parent, contextStatsKey{}, &contextStats{})
+}
+
+// NewContextWithBudget is like NewContext, but also sets a budget for the
+// queries made within the returned context. This can be used as a guardrail
+// for latency-sensitive endpoints, e.g.
+//  ctx = gormzap.NewContextWithBudget(ctx, gormzap.Budget{MaxQueries: 10})
+func NewContextWithBudget(parent context.Context, b Budget) context.Context {
+	return context.WithValue(parent, contextStatsKey{}, &contextStats{budget: b})
+}
+
+// ContextStats returns the number of queries made within ctx and total time
+// they spent in the database, e.g. to log them at the end of a request. ctx
+// must be created with NewContext, otherwise zeros are returned.
+func ContextStats(ctx context.Context) (queries int64, dbTime time.Duration) {
+	stats, ok := ctx.Value(contextStatsKey{}).(*contextStats)
+	if !ok {
+		return 0, 0
+	}
+	return stats.load()
+}
+
+func (s *contextStats) load() (queries int64, dbTime time.Duration) {
+	return atomic.LoadInt64(&s.queries), time.Duration(atomic.LoadInt64(&s.duration))
+}
+
+// WithTraceIDs returns Logger option that sets func extracting trace and span
+// IDs from the context of ContextLogger, e.g. from OpenTelemetry span
+// context. Non-empty IDs are logged as "sql.trace_id" and "sql.span_id"
+// fields.
+func WithTraceIDs(f func(ctx context.Context) (traceID, spanID string)) LoggerOption {
+	return func(l *Logger) {
+		l.traceIDs = f
+	}
+}
+
+// WithContext returns a logger bound to ctx. If ctx was not created with
+// NewContext, queries are numbered by the returned logger alone.
+func (l *Logger) WithContext(ctx context.Context) *ContextLogger {
+	stats, ok := ctx.Value(contextStatsKey{}).(*contextStats)
+	if !ok {
+		stats = &contextStats{}
+	}
+	return &ContextLogger{
+		logger: l,
+		ctx:    ctx,
+		stats:  stats,
+	}
+}
+
+// Context returns the context the logger is bound to.
+func (c *ContextLogger) Context() context.Context {
+	return c.ctx
+}
+
+// Print implements gorm's logger interface.
+func (c *ContextLogger) Print(values ...interface{}) {
+	l := c.logger.load()
+	l.log(c.newRecord(l, values...))
+}
+
+// newRecord returns record of values with the context fields.
+func (c *ContextLogger) newRecord(l *Logger, values ...interface{}) Record {
+	return c.contextRecord(l, l.newRecord(values...))
+}
+
+// contextRecord sets the context fields of rec.
+func (c *ContextLogger) contextRecord(l *Logger, rec Record) Record {
+	rec.ctx = c.ctx
+	if l.traceIDs != nil {
+		rec.TraceID, rec.SpanID = l.traceIDs(c.ctx)
+	}
+	if r, ok := contextRetry(c.ctx); ok {
+		rec.Attempt, rec.Backoff = r.attempt, r.backoff
+	}
+	if rec.SQL != "" {
+		if deadline, ok := c.ctx.Deadline(); ok {
+			rec.HasDeadline = true
+			rec.DeadlineRemaining = time.Until(deadline)
+		}
+	}
+	if rec.SQL != "" && c.stats != nil {
+		rec.Seq = atomic.AddInt64(&c.stats.queries, 1)
+		rec.DBTime = time.Duration(atomic.AddInt64(&c.stats.duration, int64(rec.Duration)))
+		if c.stats.budget.exceeded(rec.Seq, rec.DBTime) {
+			rec.BudgetExceeded = true
+			escalate(&rec, zapcore.WarnLevel)
+		}
+	}
+	return rec
+}
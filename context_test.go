@@ -0,0 +1,113 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestLogger_WithContext(t *testing.T) {
+	t.Run("numbers queries", func(t *testing.T) {
+		l, buf := logger()
+
+		cl := l.WithContext(context.Background())
+		cl.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+		cl.Print("/some/file.go:35", errors.New("some serious error!"))
+		cl.Print("sql", "/some/file.go:36", time.Millisecond*5, "SELECT 2", []interface{}{}, int64(1))
+
+		expected := []string{
+			`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms"}`,
+			`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:35"}`,
+			`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:36","sql.duration":"5ms","sql.query":"SELECT 2","sql.rows_affected":1,"sql.seq":2,"sql.db_time":"10ms"}`,
+		}
+		lines := buf.Lines()
+		for i, e := range expected {
+			if lines[i] != e {
+				t.Fatalf("Expected %s but got %s", e, lines[i])
+			}
+		}
+	})
+
+	t.Run("shares counter via NewContext", func(t *testing.T) {
+		l, buf := logger()
+
+		ctx := gormzap.NewContext(context.Background())
+		l.WithContext(ctx).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+		l.WithContext(ctx).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":2,"sql.db_time":"10ms"}`
+		if actual := buf.Lines()[1]; actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+
+		queries, dbTime := gormzap.ContextStats(ctx)
+		if queries != 2 || dbTime != time.Millisecond*10 {
+			t.Fatalf("Expected 2 queries and 10ms but got %d and %s", queries, dbTime)
+		}
+	})
+
+	t.Run("deadline remaining", func(t *testing.T) {
+		l, records := observer()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		l.WithContext(context.Background()).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+		l.WithContext(ctx).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+		recs := records.AllRecords()
+		if recs[0].HasDeadline {
+			t.Fatalf("Expected no deadline but got %s", recs[0].DeadlineRemaining)
+		}
+		if !recs[1].HasDeadline || recs[1].DeadlineRemaining <= 0 || recs[1].DeadlineRemaining > time.Hour {
+			t.Fatalf("Expected deadline remaining within an hour but got %s", recs[1].DeadlineRemaining)
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			budget gormzap.Budget
+		}{
+			{name: "max queries", budget: gormzap.Budget{MaxQueries: 1}},
+			{name: "max db time", budget: gormzap.Budget{MaxDBTime: time.Millisecond * 7}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				l, buf := logger()
+
+				cl := l.WithContext(gormzap.NewContextWithBudget(context.Background(), tc.budget))
+				cl.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+				cl.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+				expected := []string{
+					`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms"}`,
+					`{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":2,"sql.db_time":"10ms","sql.budget_exceeded":true}`,
+				}
+				lines := buf.Lines()
+				for i, e := range expected {
+					if lines[i] != e {
+						t.Fatalf("Expected %s but got %s", e, lines[i])
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("trace ids", func(t *testing.T) {
+		l, buf := logger(gormzap.WithTraceIDs(func(ctx context.Context) (string, string) {
+			return "trace", "span"
+		}))
+
+		l.WithContext(context.Background()).Print("/some/file.go:32", errors.New("some serious error!"))
+
+		expected := `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","sql.trace_id":"trace","sql.span_id":"span"}`
+		if actual := buf.Lines()[0]; actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package gormzap
+
+import "context"
+
+// WithContextlessTag returns Logger option that tags records of queries made
+// without a request context with "sql.contextless" field, which helps to
+// enforce context propagation down to the database layer. A query is made
+// without a context if it is logged by a logger not bound to one with
+// WithContext, or bound to context.Background() or context.TODO().
+func WithContextlessTag() LoggerOption {
+	return func(l *Logger) {
+		l.contextlessTag = true
+	}
+}
+
+// isContextless reports whether ctx is not a request context.
+func isContextless(ctx context.Context) bool {
+	return ctx == nil || ctx == context.Background() || ctx == context.TODO()
+}
@@ -0,0 +1,33 @@
+package gormzap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+type ctxKey struct{}
+
+func TestWithContextlessTag(t *testing.T) {
+	l, buf := logger(gormzap.WithContextlessTag())
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.WithContext(context.Background()).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.WithContext(ctx).Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.contextless":true}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms","sql.contextless":true}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package gormzap
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvLevel               = "GORMZAP_LEVEL"
+	EnvSlowThreshold       = "GORMZAP_SLOW_THRESHOLD"
+	EnvRowsAffectedWarning = "GORMZAP_ROWS_AFFECTED_WARNING"
+	EnvLogValues           = "GORMZAP_LOG_VALUES"
+	EnvMaxValueLen         = "GORMZAP_MAX_VALUE_LEN"
+	EnvFieldPrefix         = "GORMZAP_FIELD_PREFIX"
+	EnvQueryHash           = "GORMZAP_QUERY_HASH"
+	EnvCommentTags         = "GORMZAP_COMMENT_TAGS"
+	EnvPrettySQL           = "GORMZAP_PRETTY_SQL"
+	EnvColors              = "GORMZAP_COLORS"
+)
+
+// ConfigFromEnv returns Config built from environment variables, so that
+// logging behavior can be changed per deployment without code changes.
+// Unset variables leave corresponding fields zero.
+//
+// Levels are parsed as zap levels ("debug", "info", etc.), durations as Go
+// durations ("200ms"), and booleans as accepted by strconv.ParseBool.
+// GORMZAP_LOG_VALUES corresponds to Config.Interpolate.
+func ConfigFromEnv() (Config, error) {
+	var cfg Config
+	var err error
+
+	if v, ok := os.LookupEnv(EnvLevel); ok {
+		if err = cfg.Level.UnmarshalText([]byte(v)); err != nil {
+			return cfg, envError(EnvLevel, err)
+		}
+	}
+	if v, ok := os.LookupEnv(EnvSlowThreshold); ok {
+		if cfg.SlowThreshold, err = time.ParseDuration(v); err != nil {
+			return cfg, envError(EnvSlowThreshold, err)
+		}
+	}
+	if v, ok := os.LookupEnv(EnvRowsAffectedWarning); ok {
+		if cfg.RowsAffectedWarning, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return cfg, envError(EnvRowsAffectedWarning, err)
+		}
+	}
+	if v, ok := os.LookupEnv(EnvMaxValueLen); ok {
+		if cfg.MaxValueLen, err = strconv.Atoi(v); err != nil {
+			return cfg, envError(EnvMaxValueLen, err)
+		}
+	}
+	cfg.FieldPrefix = os.Getenv(EnvFieldPrefix)
+
+	bools := []struct {
+		name  string
+		value *bool
+	}{
+		{EnvLogValues, &cfg.Interpolate},
+		{EnvQueryHash, &cfg.QueryHash},
+		{EnvCommentTags, &cfg.CommentTags},
+		{EnvPrettySQL, &cfg.PrettySQL},
+		{EnvColors, &cfg.Colors},
+	}
+	for _, b := range bools {
+		if v, ok := os.LookupEnv(b.name); ok {
+			if *b.value, err = strconv.ParseBool(v); err != nil {
+				return cfg, envError(b.name, err)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+func envError(name string, err error) error {
+	return fmt.Errorf("gormzap: invalid %s: %v", name, err)
+}
@@ -0,0 +1,67 @@
+package gormzap_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		setenv(t, map[string]string{
+			gormzap.EnvLevel:         "warn",
+			gormzap.EnvSlowThreshold: "150ms",
+			gormzap.EnvLogValues:     "true",
+			gormzap.EnvMaxValueLen:   "-1",
+			gormzap.EnvFieldPrefix:   "db.",
+		})
+
+		cfg, err := gormzap.ConfigFromEnv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := gormzap.Config{
+			Level:         zap.WarnLevel,
+			SlowThreshold: time.Millisecond * 150,
+			Interpolate:   true,
+			MaxValueLen:   -1,
+			FieldPrefix:   "db.",
+		}
+		if cfg != expected {
+			t.Fatalf("Expected %+v but got %+v", expected, cfg)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		setenv(t, map[string]string{
+			gormzap.EnvSlowThreshold: "soon",
+		})
+
+		_, err := gormzap.ConfigFromEnv()
+		if err == nil {
+			t.Fatalf("Expected error but got nil")
+		}
+
+		expected := `gormzap: invalid GORMZAP_SLOW_THRESHOLD: time: invalid duration "soon"`
+		if err.Error() != expected {
+			t.Fatalf("Expected %s but got %s", expected, err)
+		}
+	})
+}
+
+func setenv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	})
+}
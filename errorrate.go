@@ -0,0 +1,154 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// minErrorRateQueries is the minimum number of queries within the window
+// required to evaluate the error rate, so that a single error at low traffic
+// does not trigger escalation.
+const minErrorRateQueries = 10
+
+// WithErrorRateEscalation returns Logger option that tracks the ratio of
+// errors to queries over a rolling window, and while it exceeds threshold,
+// logs query records with at least the given level, e.g. info or warn. This
+// gives automatic extra visibility during incidents. Changes of the state are
+// logged as separate records with "sql.error_rate" field.
+//
+// The rate is evaluated only when at least 10 queries are made within the
+// window. If threshold or window is zero or negative, the option is disabled.
+func WithErrorRateEscalation(threshold float64, window time.Duration, level zapcore.Level) LoggerOption {
+	return func(l *Logger) {
+		if threshold <= 0 || window <= 0 {
+			l.errorRate = nil
+			return
+		}
+		l.errorRate = &errorRate{
+			threshold: threshold,
+			window:    window,
+			level:     level,
+		}
+	}
+}
+
+// errorRate tracks error rate over a rolling window approximated with two
+// fixed windows: the current one and the previous one, weighted by the part
+// of it still within the rolling window.
+//
+// Records are counted with atomics, and the lock is only taken to switch
+// windows, so that the tracker does not serialize queries. Records counted
+// concurrently with the switch may be attributed to either window.
+type errorRate struct {
+	threshold float64
+	window    time.Duration
+	level     zapcore.Level
+
+	// start is the start of the current window in Unix nanoseconds.
+	start       int64
+	queries     int64
+	errors      int64
+	prevQueries int64
+	prevErrors  int64
+	active      int32
+
+	mu sync.Mutex
+}
+
+// observe counts the record and returns the current rate, whether query
+// records should be escalated, and whether the escalation state has changed.
+func (e *errorRate) observe(rec Record, now time.Time) (rate float64, active, changed bool) {
+	n := now.UnixNano()
+	start := atomic.LoadInt64(&e.start)
+	if n-start >= int64(e.window) {
+		start = e.rotate(n)
+	}
+
+	queries := atomic.LoadInt64(&e.queries)
+	if rec.SQL != "" {
+		queries = atomic.AddInt64(&e.queries, 1)
+	}
+	errors := atomic.LoadInt64(&e.errors)
+	if rec.Level >= zapcore.ErrorLevel {
+		errors = atomic.AddInt64(&e.errors, 1)
+	}
+
+	weight := 1 - float64(n-start)/float64(e.window)
+	if weight < 0 {
+		weight = 0
+	}
+	weightedQueries := float64(queries) + float64(atomic.LoadInt64(&e.prevQueries))*weight
+	weightedErrors := float64(errors) + float64(atomic.LoadInt64(&e.prevErrors))*weight
+
+	wasActive := atomic.LoadInt32(&e.active) == 1
+	if weightedQueries < minErrorRateQueries {
+		return 0, wasActive, false
+	}
+
+	rate = weightedErrors / weightedQueries
+	active = rate > e.threshold
+	if active != wasActive && atomic.CompareAndSwapInt32(&e.active, boolToInt32(wasActive), boolToInt32(active)) {
+		return rate, active, true
+	}
+	return rate, atomic.LoadInt32(&e.active) == 1, false
+}
+
+// rotate switches to the window containing n, unless a concurrent call has
+// already done so, and returns its start.
+func (e *errorRate) rotate(n int64) int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	start := atomic.LoadInt64(&e.start)
+	switch elapsed := n - start; {
+	case elapsed >= 2*int64(e.window):
+		atomic.StoreInt64(&e.prevQueries, 0)
+		atomic.StoreInt64(&e.prevErrors, 0)
+		start = n
+	case elapsed >= int64(e.window):
+		atomic.StoreInt64(&e.prevQueries, atomic.LoadInt64(&e.queries))
+		atomic.StoreInt64(&e.prevErrors, atomic.LoadInt64(&e.errors))
+		start += int64(e.window)
+	default:
+		return start
+	}
+	atomic.StoreInt64(&e.queries, 0)
+	atomic.StoreInt64(&e.errors, 0)
+	atomic.StoreInt64(&e.start, start)
+	return start
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// observeErrorRate escalates query record if the error rate is above the
+// threshold, and logs changes of the escalation state.
+func (l *Logger) observeErrorRate(rec *Record) {
+	rate, active, changed := l.errorRate.observe(*rec, time.Now())
+
+	if changed {
+		msg := "gormzap: error rate escalation started"
+		level := zapcore.WarnLevel
+		if !active {
+			msg = "gormzap: error rate escalation ended"
+			level = zapcore.InfoLevel
+		}
+		l.write(Record{
+			Message: msg,
+			Level:   level,
+			Fields:  []zapcore.Field{zap.Float64("sql.error_rate", rate)},
+		})
+	}
+
+	if active && rec.SQL != "" {
+		escalate(rec, l.errorRate.level)
+	}
+}
@@ -0,0 +1,49 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestWithErrorRateEscalation(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithLevel(zap.DebugLevel),
+		gormzap.WithErrorRateEscalation(0.5, time.Minute, zap.WarnLevel),
+	)
+
+	query := func() {
+		l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	}
+
+	for i := 0; i < 9; i++ {
+		query()
+		l.Print("/some/file.go:32", errors.New("some serious error!"))
+	}
+	// The 10th query makes the rate evaluated: 9 errors per 10 queries.
+	query()
+	for i := 0; i < 10; i++ {
+		query()
+	}
+
+	expectedStarted := `{"level":"warn","msg":"gormzap: error rate escalation started","sql.source":"","sql.error_rate":0.9}`
+	expectedQuery := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1}`
+	expectedEnded := `{"level":"info","msg":"gormzap: error rate escalation ended","sql.source":"","sql.error_rate":0.5}`
+
+	lines := buf.Lines()
+	if lines[18] != expectedStarted {
+		t.Fatalf("Expected %s but got %s", expectedStarted, lines[18])
+	}
+	if lines[19] != expectedQuery {
+		t.Fatalf("Expected %s but got %s", expectedQuery, lines[19])
+	}
+	if lines[27] != expectedEnded {
+		t.Fatalf("Expected %s but got %s", expectedEnded, lines[27])
+	}
+	if last := lines[len(lines)-1]; last[:16] != `{"level":"debug"` {
+		t.Fatalf("Expected debug level after escalation ended but got %s", last)
+	}
+}
@@ -0,0 +1,65 @@
+package gormzap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// errorStatementLen is the maximum length of "error.statement" field.
+const errorStatementLen = 256
+
+// WithErrorTags returns Logger option that adds fields to error records which
+// zap to Sentry bridges use to group issues: "error.type" with the Go type of
+// the error, "error.fingerprint" with a stable hash of the error type and
+// normalized message or statement, and "error.statement" with the statement
+// truncated to 256 bytes, if the record has one.
+//
+// Errors differing only in literals, e.g. IDs in the message, have the same
+// fingerprint.
+func WithErrorTags() LoggerOption {
+	return func(l *Logger) {
+		l.errorTags = true
+	}
+}
+
+// errorTagFields returns error tag fields of the record.
+func errorTagFields(rec Record) []zapcore.Field {
+	errType := "gorm"
+	if rec.Err != nil {
+		errType = fmt.Sprintf("%T", rec.Err)
+	}
+
+	text := normalizeSQL(rec.Message)
+	if rec.Statement != "" {
+		text = rec.normalized()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(errType))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+
+	fields := []zapcore.Field{
+		zap.String("error.type", errType),
+		zap.String("error.fingerprint", fmt.Sprintf("%016x", h.Sum64())),
+	}
+	if rec.Statement != "" {
+		fields = append(fields, zap.String("error.statement", truncate(rec.Statement, errorStatementLen)))
+	}
+	return fields
+}
+
+// truncate cuts s to at most n bytes at rune boundary.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,69 @@
+package gormzap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestWithErrorTags(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		l, buf := logger(gormzap.WithErrorTags())
+
+		l.Print("/some/file.go:32", fmt.Errorf("user %d not found", 1))
+		l.Print("/some/file.go:32", fmt.Errorf("user %d not found", 2))
+
+		lines := buf.Lines()
+		if !strings.Contains(lines[0], `"error.type":"*errors.errorString"`) {
+			t.Fatalf("Expected error type in %s", lines[0])
+		}
+		if !strings.Contains(lines[0], `"error.fingerprint":"`) {
+			t.Fatalf("Expected error fingerprint in %s", lines[0])
+		}
+
+		first := strings.Replace(lines[0], "user 1", "user 2", 1)
+		if first != lines[1] {
+			t.Fatalf("Expected errors to have the same fingerprint: %s and %s", lines[0], lines[1])
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		l, buf := logger(
+			gormzap.WithErrorTags(),
+			gormzap.WithRules(gormzap.UnboundedWriteRule(zap.ErrorLevel)),
+		)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"DELETE FROM users",
+			[]interface{}{},
+			int64(10),
+		)
+
+		actual := buf.Lines()[0]
+		for _, e := range []string{
+			`"error.type":"gorm"`,
+			`"error.statement":"DELETE FROM users"`,
+		} {
+			if !strings.Contains(actual, e) {
+				t.Fatalf("Expected %s to contain %s", actual, e)
+			}
+		}
+	})
+
+	t.Run("not error", func(t *testing.T) {
+		l, buf := logger(gormzap.WithErrorTags())
+
+		l.Print("log", "/some/file.go:33", "foo")
+
+		if actual := buf.Lines()[0]; strings.Contains(actual, "error.") {
+			t.Fatalf("Expected no error tags in %s", actual)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package gormzap
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// explainTimeout limits the time spent on fetching a query plan, as it is
+// done synchronously on the query goroutine.
+const explainTimeout = time.Second
+
+// QueryPlan is a query execution plan obtained with EXPLAIN.
+type QueryPlan struct {
+	// Text is the plan as returned by the database.
+	Text string
+
+	// The following fields are parsed from the plan for postgres and mysql
+	// dialects, and are left zero for others.
+
+	// Cost is the estimated total cost of the query, in database-specific units.
+	Cost float64
+	// Rows is the estimated number of rows the query examines.
+	Rows int64
+	// SeqScan shows if the plan contains a full table scan.
+	SeqScan bool
+}
+
+// WithExplain returns Logger option that runs EXPLAIN for slow queries using
+// db and attaches the resulting plan to the record. Dialect is a gorm dialect
+// name, e.g. "postgres", "mysql" or "sqlite3", which determines the EXPLAIN
+// syntax. For postgres and mysql the plan is also parsed into cost, rows and
+// sequential scan fields.
+//
+// Only queries marked as slow are explained, so this option takes effect
+// together with WithSlowThreshold. Note that EXPLAIN is run without ANALYZE,
+// so the statement itself is never executed again.
+func WithExplain(db *sql.DB, dialect string) LoggerOption {
+	return func(l *Logger) {
+		l.explainDB = db
+		l.explainDialect = dialect
+	}
+}
+
+func (l *Logger) explain(rec *Record) {
+	switch rec.Operation() {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE", "WITH":
+	default:
+		return
+	}
+
+	columns, rows, err := l.queryPlan(rec.Statement, rec.Args)
+	if err != nil {
+		rec.Fields = append(rec.Fields, zap.String("sql.plan_error", err.Error()))
+		return
+	}
+	rec.Plan = parsePlan(l.explainDialect, columns, rows)
+}
+
+func (l *Logger) queryPlan(statement string, args []interface{}) ([]string, [][]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	rows, err := l.explainDB.QueryContext(ctx, explainPrefix(l.explainDialect)+statement, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result [][]string
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = v.String
+		}
+		result = append(result, cells)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return columns, result, nil
+}
+
+func explainPrefix(dialect string) string {
+	switch dialect {
+	case "sqlite3", "sqlite":
+		return "EXPLAIN QUERY PLAN "
+	default:
+		return "EXPLAIN "
+	}
+}
+
+func parsePlan(dialect string, columns []string, rows [][]string) *QueryPlan {
+	lines := make([]string, len(rows))
+	for i, cells := range rows {
+		lines[i] = strings.Join(cells, "\t")
+	}
+	plan := &QueryPlan{Text: strings.Join(lines, "\n")}
+
+	switch dialect {
+	case "postgres":
+		parsePostgresPlan(plan, lines)
+	case "mysql":
+		parseMySQLPlan(plan, columns, rows)
+	}
+
+	return plan
+}
+
+var postgresCostRegexp = regexp.MustCompile(`\(cost=[\d.]+\.\.([\d.]+) rows=(\d+)`)
+
+// parsePostgresPlan parses text format of postgres plan. Cost and rows are
+// taken from the top plan node.
+func parsePostgresPlan(plan *QueryPlan, lines []string) {
+	for i, line := range lines {
+		if i == 0 {
+			if m := postgresCostRegexp.FindStringSubmatch(line); m != nil {
+				plan.Cost, _ = strconv.ParseFloat(m[1], 64)
+				plan.Rows, _ = strconv.ParseInt(m[2], 10, 64)
+			}
+		}
+		if strings.Contains(line, "Seq Scan") {
+			plan.SeqScan = true
+		}
+	}
+}
+
+// parseMySQLPlan parses tabular format of mysql plan. Rows is the product of
+// rows examined per table, as suggested by mysql documentation. Cost is not
+// available in this format.
+func parseMySQLPlan(plan *QueryPlan, columns []string, rows [][]string) {
+	typeIdx, rowsIdx := -1, -1
+	for i, c := range columns {
+		switch strings.ToLower(c) {
+		case "type":
+			typeIdx = i
+		case "rows":
+			rowsIdx = i
+		}
+	}
+
+	for _, cells := range rows {
+		if typeIdx != -1 && cells[typeIdx] == "ALL" {
+			plan.SeqScan = true
+		}
+		if rowsIdx != -1 {
+			if n, err := strconv.ParseInt(cells[rowsIdx], 10, 64); err == nil {
+				if plan.Rows == 0 {
+					plan.Rows = n
+				} else {
+					plan.Rows *= n
+				}
+			}
+		}
+	}
+}
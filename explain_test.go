@@ -0,0 +1,217 @@
+package gormzap_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithExplain(t *testing.T) {
+	t.Run("slow query", func(t *testing.T) {
+		drv := &explainDriver{
+			columns: []string{"QUERY PLAN"},
+			rows: [][]driver.Value{
+				{"Seq Scan on test  (cost=0.00..35.50 rows=10 width=4)"},
+				{"  Filter: (id = 42)"},
+			},
+		}
+		l, buf := logger(
+			gormzap.WithSlowThreshold(time.Millisecond*100),
+			gormzap.WithExplain(openExplainDB(t, drv), "postgres"),
+		)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*150,
+			"SELECT * FROM test WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"150ms","sql.query":"SELECT * FROM test WHERE id = 42","sql.rows_affected":1,"sql.slow":true,"sql.plan":"Seq Scan on test  (cost=0.00..35.50 rows=10 width=4)\n  Filter: (id = 42)","sql.plan.cost":35.5,"sql.plan.rows":10,"sql.plan.seq_scan":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+
+		if drv.query != "EXPLAIN SELECT * FROM test WHERE id = $1" {
+			t.Fatalf("Unexpected explain query %q", drv.query)
+		}
+	})
+
+	t.Run("fast query", func(t *testing.T) {
+		drv := &explainDriver{}
+		l, buf := logger(
+			gormzap.WithSlowThreshold(time.Millisecond*100),
+			gormzap.WithExplain(openExplainDB(t, drv), "postgres"),
+		)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM test WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE id = 42","sql.rows_affected":1}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+
+		if drv.query != "" {
+			t.Fatalf("Expected no explain query but got %q", drv.query)
+		}
+	})
+
+	t.Run("mysql plan", func(t *testing.T) {
+		drv := &explainDriver{
+			columns: []string{"id", "select_type", "table", "type", "key", "rows", "Extra"},
+			rows: [][]driver.Value{
+				{"1", "SIMPLE", "a", "ALL", nil, "100", "Using where"},
+				{"1", "SIMPLE", "b", "ref", "a_id", "3", nil},
+			},
+		}
+		l, buf := logger(
+			gormzap.WithSlowThreshold(time.Millisecond*100),
+			gormzap.WithExplain(openExplainDB(t, drv), "mysql"),
+		)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*150,
+			"SELECT * FROM a JOIN b ON b.a_id = a.id",
+			[]interface{}{},
+			int64(1),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"150ms","sql.query":"SELECT * FROM a JOIN b ON b.a_id = a.id","sql.rows_affected":1,"sql.slow":true,"sql.plan":"1\tSIMPLE\ta\tALL\t\t100\tUsing where\n1\tSIMPLE\tb\tref\ta_id\t3\t","sql.plan.cost":0,"sql.plan.rows":300,"sql.plan.seq_scan":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("explain error", func(t *testing.T) {
+		drv := &explainDriver{err: errors.New("syntax error")}
+		l, buf := logger(
+			gormzap.WithSlowThreshold(time.Millisecond*100),
+			gormzap.WithExplain(openExplainDB(t, drv), "mysql"),
+		)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*150,
+			"SELECT * FROM test WHERE id = ?",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"150ms","sql.query":"SELECT * FROM test WHERE id = 42","sql.rows_affected":1,"sql.slow":true,"sql.plan_error":"syntax error"}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func openExplainDB(t *testing.T, drv *explainDriver) *sql.DB {
+	db := sql.OpenDB(drv)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// explainDriver is a database/sql driver and connector that returns
+// predefined rows for any query and remembers the last query.
+type explainDriver struct {
+	columns []string
+	rows    [][]driver.Value
+	err     error
+
+	query string
+}
+
+func (d *explainDriver) Open(name string) (driver.Conn, error) {
+	return explainConn{d}, nil
+}
+
+func (d *explainDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return explainConn{d}, nil
+}
+
+func (d *explainDriver) Driver() driver.Driver {
+	return d
+}
+
+type explainConn struct {
+	d *explainDriver
+}
+
+func (c explainConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.query = query
+	if c.d.err != nil {
+		return nil, c.d.err
+	}
+	return explainStmt{c.d}, nil
+}
+
+func (c explainConn) Close() error {
+	return nil
+}
+
+func (c explainConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type explainStmt struct {
+	d *explainDriver
+}
+
+func (s explainStmt) Close() error {
+	return nil
+}
+
+func (s explainStmt) NumInput() int {
+	return -1
+}
+
+func (s explainStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s explainStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &explainRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type explainRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *explainRows) Columns() []string {
+	return r.columns
+}
+
+func (r *explainRows) Close() error {
+	return nil
+}
+
+func (r *explainRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,196 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Exporter exports batches of records, e.g. publishes them to Kafka, NATS or
+// SQS. Use it with ExportSink, which handles batching, retries and
+// backpressure.
+type Exporter interface {
+	Export(batch []Record) error
+}
+
+// ExporterFunc is an adapter to allow the use of ordinary functions as
+// exporters.
+type ExporterFunc func(batch []Record) error
+
+// Export implements Exporter.
+func (f ExporterFunc) Export(batch []Record) error {
+	return f(batch)
+}
+
+// Defaults of ExportConfig.
+const (
+	DefaultExportBatchSize     = 100
+	DefaultExportFlushInterval = time.Second
+	DefaultExportBufferSize    = 10000
+	DefaultExportRetryBackoff  = 100 * time.Millisecond
+)
+
+// ExportConfig configures ExportSink. Zero value is a valid configuration
+// using the defaults.
+type ExportConfig struct {
+	// BatchSize is the maximum number of records in a batch. By default,
+	// DefaultExportBatchSize is used.
+	BatchSize int
+	// FlushInterval is the maximum time a record waits for its batch to be
+	// filled. By default, DefaultExportFlushInterval is used.
+	FlushInterval time.Duration
+	// BufferSize is the maximum number of records waiting to be exported.
+	// By default, DefaultExportBufferSize is used.
+	BufferSize int
+
+	// MaxRetries is the number of times a failed batch is retried before it
+	// is dropped. Zero disables retries.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, which is doubled for
+	// each next one. By default, DefaultExportRetryBackoff is used.
+	RetryBackoff time.Duration
+
+	// Block shows if WriteRecord blocks when the buffer is full, slowing
+	// down queries until the exporter catches up. By default, records are
+	// dropped instead.
+	Block bool
+
+	// OnError, if set, is called when a batch is dropped after it fails to
+	// export with the last error.
+	OnError func(err error, batch []Record)
+}
+
+// ExportSink is RecordSink that exports records in batches with Exporter.
+// Batches are exported sequentially from a separate goroutine.
+type ExportSink struct {
+	exporter Exporter
+	cfg      ExportConfig
+
+	mu      sync.RWMutex
+	closed  bool
+	records chan Record
+	done    chan struct{}
+
+	dropped int64
+}
+
+// NewExportSink returns a new ExportSink exporting records with e. The sink
+// must be closed with Close to export buffered records.
+func NewExportSink(e Exporter, cfg ExportConfig) *ExportSink {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultExportBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultExportFlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultExportBufferSize
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultExportRetryBackoff
+	}
+
+	s := &ExportSink{
+		exporter: e,
+		cfg:      cfg,
+		records:  make(chan Record, cfg.BufferSize),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// WriteRecord implements RecordSink.
+func (s *ExportSink) WriteRecord(r Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		atomic.AddInt64(&s.dropped, 1)
+		return
+	}
+
+	if s.cfg.Block {
+		s.records <- r
+		return
+	}
+
+	select {
+	case s.records <- r:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of records dropped so far, because the buffer
+// was full, the sink was closed, or their batch failed to export.
+func (s *ExportSink) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// Close exports buffered records and stops the sink. Records written after
+// Close are dropped.
+func (s *ExportSink) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.records)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+}
+
+func (s *ExportSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case r, ok := <-s.records:
+			if !ok {
+				s.export(batch)
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		s.export(batch)
+		batch = make([]Record, 0, s.cfg.BatchSize)
+	}
+}
+
+// export exports the batch, retrying on errors.
+func (s *ExportSink) export(batch []Record) {
+	if len(batch) == 0 {
+		return
+	}
+
+	backoff := s.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.exporter.Export(batch)
+		if err == nil {
+			return
+		}
+		if attempt == s.cfg.MaxRetries {
+			atomic.AddInt64(&s.dropped, int64(len(batch)))
+			if s.cfg.OnError != nil {
+				s.cfg.OnError(err, batch)
+			}
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,103 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestExportSink(t *testing.T) {
+	t.Run("batches", func(t *testing.T) {
+		var sizes []int
+		sink := gormzap.NewExportSink(gormzap.ExporterFunc(func(batch []gormzap.Record) error {
+			sizes = append(sizes, len(batch))
+			return nil
+		}), gormzap.ExportConfig{BatchSize: 2, FlushInterval: time.Hour})
+
+		l, _ := logger(gormzap.WithSinks(sink))
+		for i := 0; i < 5; i++ {
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+		}
+		sink.Close()
+
+		if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+			t.Fatalf("Expected batches of 2, 2 and 1 records but got %v", sizes)
+		}
+		if n := sink.Dropped(); n != 0 {
+			t.Fatalf("Expected no dropped records but got %d", n)
+		}
+	})
+
+	t.Run("flush interval", func(t *testing.T) {
+		exported := make(chan int, 1)
+		sink := gormzap.NewExportSink(gormzap.ExporterFunc(func(batch []gormzap.Record) error {
+			exported <- len(batch)
+			return nil
+		}), gormzap.ExportConfig{FlushInterval: time.Millisecond * 10})
+		defer sink.Close()
+
+		sink.WriteRecord(gormzap.Record{Message: "some message"})
+
+		select {
+		case n := <-exported:
+			if n != 1 {
+				t.Fatalf("Expected batch of 1 record but got %d", n)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected batch to be exported on flush interval")
+		}
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		attempts := 0
+		var dropped []gormzap.Record
+		sink := gormzap.NewExportSink(gormzap.ExporterFunc(func(batch []gormzap.Record) error {
+			attempts++
+			if batch[0].Message == "bad" || attempts < 3 {
+				return errors.New("broker unavailable")
+			}
+			return nil
+		}), gormzap.ExportConfig{
+			BatchSize:    1,
+			MaxRetries:   2,
+			RetryBackoff: time.Millisecond,
+			OnError: func(err error, batch []gormzap.Record) {
+				dropped = append(dropped, batch...)
+			},
+		})
+
+		sink.WriteRecord(gormzap.Record{Message: "good"})
+		sink.WriteRecord(gormzap.Record{Message: "bad"})
+		sink.Close()
+
+		if attempts != 6 {
+			t.Fatalf("Expected 6 attempts but got %d", attempts)
+		}
+		if len(dropped) != 1 || dropped[0].Message != "bad" || sink.Dropped() != 1 {
+			t.Fatalf("Expected bad record to be dropped but got %v", dropped)
+		}
+	})
+
+	t.Run("full buffer", func(t *testing.T) {
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		sink := gormzap.NewExportSink(gormzap.ExporterFunc(func(batch []gormzap.Record) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}), gormzap.ExportConfig{BatchSize: 1, BufferSize: 1})
+
+		sink.WriteRecord(gormzap.Record{Message: "exporting"})
+		<-started
+		sink.WriteRecord(gormzap.Record{Message: "buffered"})
+		sink.WriteRecord(gormzap.Record{Message: "dropped"})
+		close(release)
+		sink.Close()
+
+		if n := sink.Dropped(); n != 1 {
+			t.Fatalf("Expected 1 dropped record but got %d", n)
+		}
+	})
+}
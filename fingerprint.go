@@ -0,0 +1,73 @@
+package gormzap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+// WithQueryHash returns Logger option that adds a short stable hash of the
+// normalized query as "sql.query_hash" field.
+//
+// Queries that differ only in bind values, literals, comments, whitespace or
+// keyword case have the same hash, so it can be used to join logs, metrics,
+// and traces on the same identifier without storing long SQL strings.
+func WithQueryHash() LoggerOption {
+	return func(l *Logger) {
+		l.queryHash = true
+	}
+}
+
+var (
+	normalizeLiteralRegexp = regexp.MustCompile(`\$\d+|\b\d+(\.\d+)?\b`)
+	normalizeSpaceRegexp   = regexp.MustCompile(`\s+`)
+	normalizeListRegexp    = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+)
+
+// normalizeSQL returns SQL statement with literals and placeholders replaced
+// with `?`, lists of them collapsed into a single `?`, comments removed,
+// whitespace collapsed and keywords lowercased. Quoted identifiers are kept
+// as is.
+func normalizeSQL(sql string) string {
+	var b strings.Builder
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		switch kind {
+		case tokenText:
+			b.WriteString(normalizeLiteralRegexp.ReplaceAllString(strings.ToLower(tok), "?"))
+		case tokenString:
+			b.WriteString("?")
+		case tokenIdent:
+			b.WriteString(tok)
+		case tokenComment:
+			b.WriteString(" ")
+		}
+	})
+
+	s := normalizeSpaceRegexp.ReplaceAllString(b.String(), " ")
+	s = normalizeListRegexp.ReplaceAllString(s, "?")
+	return strings.TrimSpace(s)
+}
+
+// NormalizeQuery returns SQL query in the normalized form used for query
+// hash: literals and placeholders are replaced with `?`, lists of them are
+// collapsed, comments are removed, whitespace is collapsed and keywords are
+// lowercased.
+func NormalizeQuery(sql string) string {
+	return normalizeSQL(sql)
+}
+
+// QueryHash returns hash of the normalized SQL query, as logged in
+// "sql.query_hash" field with WithQueryHash. It can be used to group logged
+// queries of the same shape, e.g. when analyzing logs.
+func QueryHash(sql string) string {
+	return hashNormalized(normalizeSQL(sql))
+}
+
+// hashNormalized returns 64-bit FNV-1a hash of the normalized statement as a
+// 16-character hex string.
+func hashNormalized(normalized string) string {
+	h := fnv.New64a()
+	h.Write([]byte(normalized))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,71 @@
+package gormzap_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithQueryHash(t *testing.T) {
+	same := []string{
+		"SELECT * FROM test WHERE id IN ($1, $2, $3) AND name = 'foo'",
+		"select *  from test\n\twhere id in ($1) and name = 'bar' -- comment",
+		"SELECT * FROM test WHERE id IN (1, 2) AND name = 'baz'",
+	}
+	different := []string{
+		`SELECT * FROM "Test" WHERE id IN ($1) AND name = 'foo'`,
+		"SELECT * FROM test WHERE id IN ($1) OR name = 'foo'",
+	}
+
+	var expected string
+	for i, sql := range append(same, different...) {
+		actual := printQueryHash(t, sql)
+		if len(actual) != 16 {
+			t.Fatalf("Unexpected hash %q", actual)
+		}
+
+		switch {
+		case i == 0:
+			expected = actual
+		case i < len(same) && actual != expected:
+			t.Fatalf("Expected hash of %q to be %s but got %s", sql, expected, actual)
+		case i >= len(same) && actual == expected:
+			t.Fatalf("Expected hash of %q to differ from %s", sql, expected)
+		}
+	}
+}
+
+func printQueryHash(t *testing.T, sql string) string {
+	l, buf := logger(gormzap.WithQueryHash())
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		sql,
+		[]interface{}{},
+		int64(1),
+	)
+
+	var rec struct {
+		QueryHash string `json:"sql.query_hash"`
+	}
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &rec); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return rec.QueryHash
+}
+
+func TestQueryHash(t *testing.T) {
+	sql := "SELECT * FROM test WHERE id IN ($1, $2) AND name = 'foo'"
+	if actual, expected := gormzap.QueryHash(sql), printQueryHash(t, sql); actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+
+	expected := "select * from test where id in (?) and name = ?"
+	if actual := gormzap.NormalizeQuery(sql); actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
@@ -0,0 +1,216 @@
+package gormzap
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// WithPrettySQL returns Logger option that formats logged queries on several
+// lines, starting a new line with each major clause (FROM, WHERE, JOIN, etc.).
+// This is mostly useful for development with a console encoder.
+func WithPrettySQL() LoggerOption {
+	return func(l *Logger) {
+		l.prettySQL = true
+	}
+}
+
+// WithColors returns Logger option that highlights keywords and string
+// literals of logged queries with ANSI color codes. This is mostly useful for
+// development with a console encoder writing to a terminal.
+func WithColors() LoggerOption {
+	return func(l *Logger) {
+		l.colors = true
+	}
+}
+
+// KeywordCase determines case of SQL keywords in the logged query.
+type KeywordCase int
+
+const (
+	// KeywordCaseAsIs logs queries as they are.
+	KeywordCaseAsIs KeywordCase = iota
+	// KeywordCaseUpper uppercases SQL keywords.
+	KeywordCaseUpper
+	// KeywordCaseLower lowercases the whole query, except for string literals
+	// and quoted identifiers.
+	KeywordCaseLower
+)
+
+// WithKeywordCase returns Logger option that sets case of SQL keywords in the
+// logged queries, so that searches and dashboards do not miss queries due to
+// inconsistent casing from different call sites. By default, KeywordCaseAsIs
+// is used.
+func WithKeywordCase(c KeywordCase) LoggerOption {
+	return func(l *Logger) {
+		l.keywordCase = c
+	}
+}
+
+const (
+	colorKeyword = "\x1b[35m"
+	colorString  = "\x1b[32m"
+	colorReset   = "\x1b[0m"
+)
+
+var (
+	clauseRegexp  = regexp.MustCompile(`(?i)\s+\b(from|where|group\s+by|order\s+by|having|limit|offset|values|set|returning|union(\s+all)?|((left|right|full|inner|cross)\s+(outer\s+)?)?join|on\s+conflict)\b`)
+	keywordRegexp = regexp.MustCompile(`(?i)\b(select|insert|into|update|delete|from|where|and|or|not|in|is|null|like|ilike|between|exists|group|order|by|having|limit|offset|values|set|returning|union|all|distinct|as|join|left|right|full|inner|outer|cross|on|conflict|do|nothing|case|when|then|else|end|asc|desc|create|alter|drop|table|index|truncate|begin|commit|rollback)\b`)
+)
+
+// formatQuery applies formatting options to the SQL query to log.
+func (l *Logger) formatQuery(sql string) string {
+	if !l.prettySQL && !l.colors && l.keywordCase == KeywordCaseAsIs {
+		return sql
+	}
+
+	var b strings.Builder
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		switch {
+		case kind == tokenText && l.keywordCase == KeywordCaseUpper:
+			tok = keywordRegexp.ReplaceAllStringFunc(tok, strings.ToUpper)
+		case kind == tokenText && l.keywordCase == KeywordCaseLower:
+			tok = strings.ToLower(tok)
+		}
+		if kind == tokenText && l.prettySQL {
+			tok = clauseRegexp.ReplaceAllString(tok, "\n$1")
+		}
+		switch {
+		case kind == tokenText && l.colors:
+			tok = keywordRegexp.ReplaceAllString(tok, colorKeyword+"$1"+colorReset)
+		case kind == tokenString && l.colors:
+			tok = colorString + tok + colorReset
+		}
+		b.WriteString(tok)
+	})
+	return b.String()
+}
+
+// WithCollapsedInLists returns Logger option that collapses IN lists with
+// more than max values or placeholders in logged queries, e.g. an IN list of
+// 1500 IDs is logged as `IN (<1500 values>)` instead of a multi-kilobyte
+// list. Record.SQL and the logged statement are collapsed, and the total
+// number of collapsed values is logged as "sql.in_list_values" field.
+// Record.Statement and Record.Args are kept intact, so that sinks replaying
+// queries get matching placeholders and values. Subqueries in IN clauses are
+// never collapsed.
+//
+// If max is zero or negative, IN lists are not collapsed.
+func WithCollapsedInLists(max int) LoggerOption {
+	return func(l *Logger) {
+		l.maxInList = max
+	}
+}
+
+// collapseInLists collapses long IN lists of the record queries.
+func (l *Logger) collapseInLists(rec *Record) {
+	var n, sn int
+	rec.SQL, n = collapseInLists(rec.SQL, l.maxInList)
+	rec.collapsedStatement, sn = collapseInLists(rec.Statement, l.maxInList)
+	if sn > n {
+		n = sn
+	}
+	if n > 0 {
+		rec.Fields = append(rec.Fields, zap.Int("sql.in_list_values", n))
+	}
+}
+
+// collapseInLists replaces IN lists of sql with more than max items with
+// their number, and returns the total number of collapsed items.
+func collapseInLists(sql string, max int) (string, int) {
+	var b strings.Builder
+	last, total := 0, 0
+
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = quoteEnd(sql, i, c) - 1
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			if n := strings.IndexByte(sql[i:], '\n'); n != -1 {
+				i += n
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if n := strings.Index(sql[i+2:], "*/"); n != -1 {
+				i += 2 + n + 1
+			} else {
+				i = len(sql)
+			}
+		case isInKeyword(sql, i):
+			j := i + 2
+			for j < len(sql) && isSpace(sql[j]) {
+				j++
+			}
+			if j == len(sql) || sql[j] != '(' {
+				continue
+			}
+			n, end := countListItems(sql, j)
+			if n <= max {
+				continue
+			}
+			b.WriteString(sql[last:j])
+			b.WriteString("(<" + strconv.Itoa(n) + " values>)")
+			last, i = end, end-1
+			total += n
+		}
+	}
+
+	if total == 0 {
+		return sql, 0
+	}
+	b.WriteString(sql[last:])
+	return b.String(), total
+}
+
+// isInKeyword reports whether sql has IN keyword at i.
+func isInKeyword(sql string, i int) bool {
+	if i+2 > len(sql) || !strings.EqualFold(sql[i:i+2], "in") {
+		return false
+	}
+	if i > 0 && isIdentChar(sql[i-1]) {
+		return false
+	}
+	return i+2 == len(sql) || !isIdentChar(sql[i+2])
+}
+
+// countListItems returns the number of items of the parenthesized list
+// starting at i, and index after its closing parenthesis. Zero is returned
+// for subqueries and unterminated lists.
+func countListItems(sql string, i int) (n, end int) {
+	body := strings.TrimLeft(sql[i+1:], " \t\r\n")
+	if len(body) >= 6 && strings.EqualFold(body[:6], "select") {
+		return 0, 0
+	}
+
+	depth, items := 0, 1
+	for j := i; j < len(sql); j++ {
+		switch c := sql[j]; c {
+		case '\'', '"', '`':
+			j = quoteEnd(sql, j, c) - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return items, j + 1
+			}
+		case ',':
+			if depth == 1 {
+				items++
+			}
+		}
+	}
+	return 0, 0
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c == '.' ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
@@ -0,0 +1,125 @@
+package gormzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithCollapsedInLists(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sql      string
+		args     []interface{}
+		expected string
+		values   int64
+	}{
+		{
+			name:     "collapsed",
+			sql:      "SELECT * FROM users WHERE id IN (?, ?, ?, ?) AND deleted_at IS NULL",
+			args:     []interface{}{1, 2, 3, 4},
+			expected: "SELECT * FROM users WHERE id IN (<4 values>) AND deleted_at IS NULL",
+			values:   4,
+		},
+		{
+			name:     "short",
+			sql:      "SELECT * FROM users WHERE id IN (?, ?)",
+			args:     []interface{}{1, 2},
+			expected: "SELECT * FROM users WHERE id IN (1, 2)",
+		},
+		{
+			name:     "literals",
+			sql:      "SELECT * FROM users WHERE name NOT IN ('a,b', 'c)', 'd', 'e') OR login in ('in (1,2,3,4)')",
+			expected: "SELECT * FROM users WHERE name NOT IN (<4 values>) OR login in ('in (1,2,3,4)')",
+			values:   4,
+		},
+		{
+			name:     "nested",
+			sql:      "SELECT * FROM t WHERE (a, b) IN ((1, 2), (3, 4), (5, 6), (7, 8), (9, 0))",
+			expected: "SELECT * FROM t WHERE (a, b) IN (<5 values>)",
+			values:   5,
+		},
+		{
+			name:     "subquery",
+			sql:      "SELECT * FROM t WHERE id IN (SELECT a, b, c, d FROM s)",
+			expected: "SELECT * FROM t WHERE id IN (SELECT a, b, c, d FROM s)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, records := observer(gormzap.WithCollapsedInLists(3))
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, tc.sql, tc.args, int64(1))
+
+			rec := records.AllRecords()[0]
+			if rec.SQL != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, rec.SQL)
+			}
+			if rec.Statement != tc.sql || len(rec.Args) != len(tc.args) {
+				t.Fatalf("Expected statement and args intact but got %s %v", rec.Statement, rec.Args)
+			}
+
+			var values int64
+			for _, f := range rec.Fields {
+				if f.Key == "sql.in_list_values" {
+					values = f.Integer
+				}
+			}
+			if values != tc.values {
+				t.Fatalf("Expected %d collapsed values but got %d", tc.values, values)
+			}
+		})
+	}
+}
+
+func TestWithCollapsedInLists_template(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithCollapsedInLists(3),
+		gormzap.WithRecordToFields(gormzap.TemplateRecordToFields),
+	)
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users WHERE id IN (?, ?, ?, ?)", []interface{}{1, 2, 3, 4}, int64(1))
+
+	expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.template":"SELECT * FROM users WHERE id IN (<4 values>)","sql.query":"SELECT * FROM users WHERE id IN (<4 values>)","sql.rows_affected":1,"sql.in_list_values":4}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithKeywordCase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		c        gormzap.KeywordCase
+		expected string
+	}{
+		{
+			name:     "as is",
+			c:        gormzap.KeywordCaseAsIs,
+			expected: `Select * from "Users" WHERE name = 'From' and Age > 1`,
+		},
+		{
+			name:     "upper",
+			c:        gormzap.KeywordCaseUpper,
+			expected: `SELECT * FROM "Users" WHERE name = 'From' AND Age > 1`,
+		},
+		{
+			name:     "lower",
+			c:        gormzap.KeywordCaseLower,
+			expected: `select * from "Users" where name = 'From' and age > 1`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, records := observer(gormzap.WithKeywordCase(tc.c))
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, `Select * from "Users" WHERE name = ? and Age > ?`, []interface{}{"From", 1}, int64(1))
+
+			if actual := records.AllRecords()[0].SQL; actual != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
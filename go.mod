@@ -1,11 +1,15 @@
 module github.com/hypnoglow/gormzap
 
+go 1.16
+
+require (
+	go.uber.org/zap v1.8.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pkg/errors v0.8.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.2.2 // indirect
+	github.com/stretchr/testify v1.8.1 // indirect
 	go.uber.org/atomic v1.3.2 // indirect
 	go.uber.org/multierr v1.1.0 // indirect
-	go.uber.org/zap v1.8.0
 )
@@ -7,12 +7,19 @@
 package gormzap
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -20,9 +27,71 @@ import (
 
 // Logger is a gorm logger implementation using zap.
 type Logger struct {
-	origin      *zap.Logger
-	level       zapcore.Level
-	encoderFunc RecordToFields
+	origin       *zap.Logger
+	level        zapcore.Level
+	levelMapping map[GormLevel]zapcore.Level
+	levelEnabler zapcore.LevelEnabler
+	encoderFunc  RecordToFields
+
+	rowsAffectedWarning int64
+
+	ddl      bool
+	ddlLevel zapcore.Level
+
+	cancelledLevel zapcore.Level
+
+	rules []Rule
+	sinks []RecordSink
+
+	errorThrottle    *errorThrottle
+	levelCounter     *LevelCounter
+	errorRate        *errorRate
+	sampler          *sampler
+	cardinalityGuard *cardinalityGuard
+
+	commentTags bool
+	queryHash   bool
+	parser      Parser
+
+	withoutValues bool
+	maxValueLen   int
+	formatter     valueFormatter
+	bytesPolicy   BytesPolicy
+	prettySQL     bool
+	colors        bool
+	keywordCase   KeywordCase
+	maxInList     int
+	fieldPrefix   string
+
+	staticFields   []zapcore.Field
+	prefixedFields []zapcore.Field
+	copyFields     bool
+	role           string
+	syslogSeverity bool
+	sourceAsCaller bool
+	withoutSource  bool
+	durationMillis bool
+	errorTags      bool
+	messageFunc    func(r Record) string
+
+	traceIDs       func(ctx context.Context) (traceID, spanID string)
+	contextlessTag bool
+
+	keepPlaceholders bool
+	maxFormattedArgs int
+	maxQueryBytes    int
+
+	slowThreshold time.Duration
+	queryTimes    bool
+	goroutineID   bool
+	argsBytes     bool
+	argsCount     bool
+
+	explainDB      *sql.DB
+	explainDialect string
+
+	// live holds configuration that replaced this one with Reconfigure.
+	live *liveConfig
 }
 
 // LoggerOption is an option for Logger.
@@ -31,9 +100,65 @@ type LoggerOption func(*Logger)
 // WithLevel returns Logger option that sets level for gorm logs.
 // It affects only general logs, e.g. those that contain SQL queries.
 // Errors will be logged with error level independently of this option.
-func WithLevel(level zapcore.Level) LoggerOption {
+//
+// Any other zapcore.LevelEnabler, e.g. zap.AtomicLevel or a custom one
+// enabling debug logs within a time window, gates records instead: they are
+// logged with their usual levels, but only if enabled by it. This allows to
+// switch query logging on and off at runtime.
+func WithLevel(level zapcore.LevelEnabler) LoggerOption {
 	return func(l *Logger) {
-		l.level = level
+		if lvl, ok := level.(zapcore.Level); ok {
+			l.level = lvl
+			l.levelEnabler = nil
+			return
+		}
+		l.levelEnabler = level
+	}
+}
+
+// GormLevel is a level of gorm logs other than SQL queries.
+type GormLevel int
+
+const (
+	// GormInfo is the level of general gorm logs, e.g. those made with
+	// Scope.Log, which are logged with the level set by WithLevel.
+	GormInfo GormLevel = iota
+	// GormError is the level of errors logged by gorm, which are logged with
+	// error level.
+	GormError
+)
+
+// WithLevelMapping returns Logger option that remaps levels of gorm logs to
+// zap levels, e.g. GormError to warn level, independently of the level of
+// SQL query records set by WithLevel. Levels missing from m keep their
+// defaults. Note that the mapped level is what level-based features see,
+// e.g. errors mapped below error level are not counted by
+// WithErrorRateEscalation.
+func WithLevelMapping(m map[GormLevel]zapcore.Level) LoggerOption {
+	return func(l *Logger) {
+		l.levelMapping = make(map[GormLevel]zapcore.Level, len(m))
+		for k, v := range m {
+			l.levelMapping[k] = v
+		}
+	}
+}
+
+// mappedLevel returns zap level gorm level is mapped to, or def if it is not.
+func (l *Logger) mappedLevel(level GormLevel, def zapcore.Level) zapcore.Level {
+	if mapped, ok := l.levelMapping[level]; ok {
+		return mapped
+	}
+	return def
+}
+
+// WithZapOptions returns Logger option that applies zap options, e.g.
+// zap.Fields, zap.WrapCore or zap.Hooks, to the zap logger the logger writes
+// to, without wrapping the zap logger shared with the application. Options
+// are applied on top of the current zap logger, so passing the same options
+// to CloneWith of the logger applies them once more.
+func WithZapOptions(opts ...zap.Option) LoggerOption {
+	return func(l *Logger) {
+		l.origin = l.origin.WithOptions(opts...)
 	}
 }
 
@@ -47,26 +172,415 @@ func WithRecordToFields(f RecordToFields) LoggerOption {
 	}
 }
 
+// WithSlowThreshold returns Logger option that marks queries taking longer
+// than d as slow. Slow queries are logged with at least warn level.
+func WithSlowThreshold(d time.Duration) LoggerOption {
+	return func(l *Logger) {
+		l.slowThreshold = d
+	}
+}
+
+// WithQueryTimes returns Logger option that logs the times queries started
+// and completed at as "sql.start" and "sql.end" fields, in addition to their
+// duration. This simplifies correlating queries with external events, e.g.
+// database failovers.
+func WithQueryTimes() LoggerOption {
+	return func(l *Logger) {
+		l.queryTimes = true
+	}
+}
+
+// WithoutValues returns Logger option that disables interpolation of bind
+// values into the logged query, so it is logged with placeholders instead.
+// This can be used to keep sensitive data out of logs.
+//
+// Note that bind values are still available in Record.Args for custom
+// encoders and are logged by ParameterizedRecordToFields.
+func WithoutValues() LoggerOption {
+	return func(l *Logger) {
+		l.withoutValues = true
+	}
+}
+
+// WithMaxValueLen returns Logger option that sets maximum length of a value
+// interpolated into the logged query. Longer values are replaced with
+// '<redacted>'. If n is zero or negative, values are never redacted.
+// By default, values longer than 255 characters are redacted.
+func WithMaxValueLen(n int) LoggerOption {
+	return func(l *Logger) {
+		l.maxValueLen = n
+	}
+}
+
+// BytesPolicy determines how []byte values are interpolated into the logged
+// query.
+type BytesPolicy int
+
+const (
+	// BytesPrintable logs values as text if all their characters are
+	// printable, and as '<binary>' otherwise. This is the default.
+	BytesPrintable BytesPolicy = iota
+	// BytesUTF8 logs values as text whenever they are valid UTF-8, including
+	// those containing newlines or other control characters.
+	BytesUTF8
+	// BytesUTF8Escaped logs values as text whenever they are valid UTF-8, like
+	// BytesUTF8, but escapes control characters, e.g. newline as \n, so that
+	// the logged query stays on a single line.
+	BytesUTF8Escaped
+)
+
+// WithBytesPolicy returns Logger option that sets policy for interpolating
+// []byte values. By default, BytesPrintable is used.
+func WithBytesPolicy(p BytesPolicy) LoggerOption {
+	return func(l *Logger) {
+		l.bytesPolicy = p
+	}
+}
+
+// WithKeepPlaceholders returns Logger option that leaves the original
+// placeholder in the logged query when a bind value cannot be rendered, e.g.
+// when its driver.Valuer returns an error, and attaches the error as
+// "sql.format_error" field. By default, such values are logged as NULL.
+func WithKeepPlaceholders() LoggerOption {
+	return func(l *Logger) {
+		l.keepPlaceholders = true
+	}
+}
+
+// WithMaxFormattedArgs returns Logger option that interpolates only the first
+// n bind values into the logged query, leaving placeholders of the rest as
+// is, and logs the number of values left out as "sql.args_omitted" field.
+// This bounds the time and log size spent on statements with thousands of
+// bind values, e.g. bulk inserts. Zero, the default, means no limit.
+func WithMaxFormattedArgs(n int) LoggerOption {
+	return func(l *Logger) {
+		l.maxFormattedArgs = n
+	}
+}
+
+// WithMaxQueryBytes returns Logger option that limits the size of the
+// logged query with interpolated values to n bytes, 1 MiB by default. Queries
+// exceeding the limit are logged as statements with placeholders instead,
+// and marked with "sql.oversize" field, so that huge bind values do not
+// produce multi-megabyte log lines. Statements exceeding the limit themselves
+// are truncated and end with "...<truncated>". Zero or negative n disables the
+// limit.
+func WithMaxQueryBytes(n int) LoggerOption {
+	return func(l *Logger) {
+		l.maxQueryBytes = n
+	}
+}
+
+// WithDurationMillis returns Logger option that logs query duration as
+// "sql.duration_ms" integer number of milliseconds instead of "sql.duration"
+// field, for log backends that require typed columns, e.g. BigQuery or
+// ClickHouse tables. It applies to any encoder func logging the duration as
+// "sql.duration" zap.Duration field, as the built-in ones do.
+func WithDurationMillis() LoggerOption {
+	return func(l *Logger) {
+		l.durationMillis = true
+	}
+}
+
+// WithFieldPrefix returns Logger option that replaces "sql." prefix of the
+// logged field keys with the given one, e.g. "db." or "gorm_".
+func WithFieldPrefix(prefix string) LoggerOption {
+	return func(l *Logger) {
+		l.fieldPrefix = prefix
+	}
+}
+
+// WithRowsAffectedWarning returns Logger option that escalates records of
+// write statements (INSERT, UPDATE, DELETE, REPLACE) to warn level when they
+// affect more than n rows. Records already logged with a higher level are left
+// untouched.
+//
+// This can be used as a guardrail for unexpectedly wide bulk mutations.
+func WithRowsAffectedWarning(n int64) LoggerOption {
+	return func(l *Logger) {
+		l.rowsAffectedWarning = n
+	}
+}
+
+// WithDDLLevel returns Logger option that marks DDL statements (CREATE,
+// ALTER, DROP, TRUNCATE, RENAME) and logs them with at least the given level,
+// so that schema changes, e.g. those made by AutoMigrate, stand out.
+func WithDDLLevel(level zapcore.Level) LoggerOption {
+	return func(l *Logger) {
+		l.ddl = true
+		l.ddlLevel = level
+	}
+}
+
 // New returns a new gorm logger implemented using zap.
 // By default it logs with debug level.
+//
+// If origin is nil, zap's global logger is used, see zap.L.
 func New(origin *zap.Logger, opts ...LoggerOption) *Logger {
+	if origin == nil {
+		origin = zap.L()
+	}
+
 	l := &Logger{
-		origin:      origin,
-		level:       zap.DebugLevel,
-		encoderFunc: DefaultRecordToFields,
+		origin:         origin,
+		level:          zap.DebugLevel,
+		cancelledLevel: zap.WarnLevel,
+		encoderFunc:    DefaultRecordToFields,
+		maxValueLen:    maxLen,
+		maxQueryBytes:  maxQueryBytes,
+		live:           &liveConfig{},
 	}
 
 	for _, o := range opts {
 		o(l)
 	}
+	l.prepare()
 
 	return l
 }
 
+// NewWithOptions returns a new gorm logger like New, but validates the
+// resulting configuration and returns an error if it is broken, e.g. if
+// origin or encoder func is nil, a threshold is negative, or options conflict
+// with each other.
+func NewWithOptions(origin *zap.Logger, opts ...LoggerOption) (*Logger, error) {
+	if origin == nil {
+		return nil, errors.New("gormzap: origin logger is nil")
+	}
+
+	l := New(origin, opts...)
+	if err := l.validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) validate() error {
+	switch {
+	case l.encoderFunc == nil:
+		return errors.New("gormzap: RecordToFields func is nil")
+	case l.slowThreshold < 0:
+		return fmt.Errorf("gormzap: negative slow threshold %s", l.slowThreshold)
+	case l.rowsAffectedWarning < 0:
+		return fmt.Errorf("gormzap: negative rows affected warning threshold %d", l.rowsAffectedWarning)
+	case l.explainDB != nil && l.slowThreshold == 0:
+		return errors.New("gormzap: explain requires slow threshold to be set")
+	}
+	return nil
+}
+
+// NewWithCore returns a new gorm logger that writes to the given zap core.
+// This is a shortcut for New(zap.New(core), opts...), convenient when the core
+// is wrapped, e.g. with sampling or tee cores.
+func NewWithCore(core zapcore.Core, opts ...LoggerOption) *Logger {
+	return New(zap.New(core), opts...)
+}
+
+// NewFromSugar returns a new gorm logger implemented using the zap logger
+// underlying the given sugared logger. If sugar is nil, zap's global logger is
+// used, as with New.
+func NewFromSugar(sugar *zap.SugaredLogger, opts ...LoggerOption) *Logger {
+	if sugar == nil {
+		return New(nil, opts...)
+	}
+	return New(sugar.Desugar(), opts...)
+}
+
+// NewNop returns a new gorm logger that discards all records. It can be used
+// in tests or to disable SQL logging without nil checks.
+func NewNop() *Logger {
+	return New(zap.NewNop())
+}
+
+// CloneWith returns an independent copy of the logger with the given options
+// applied on top of its configuration. This can be used to specialize one base
+// configuration per gorm connection.
+func (l *Logger) CloneWith(opts ...LoggerOption) *Logger {
+	c := l.load().clone()
+	for _, o := range opts {
+		o(c)
+	}
+	c.prepare()
+	return c
+}
+
+func (l *Logger) clone() *Logger {
+	c := *l
+	c.rules = append([]Rule(nil), l.rules...)
+	c.sinks = append([]RecordSink(nil), l.sinks...)
+	c.staticFields = append([]zapcore.Field(nil), l.staticFields...)
+	c.live = &liveConfig{}
+	return &c
+}
+
+// Level returns level the logger uses for general logs.
+func (l *Logger) Level() zapcore.Level {
+	return l.load().level
+}
+
+// SlowThreshold returns the slow query threshold, or zero if slow query
+// detection is disabled.
+func (l *Logger) SlowThreshold() time.Duration {
+	return l.load().slowThreshold
+}
+
+// Encoder returns func the logger uses to encode records into zap fields.
+func (l *Logger) Encoder() RecordToFields {
+	return l.load().encoderFunc
+}
+
 // Print implements gorm's logger interface.
+// It is safe for concurrent use and does not take any locks by itself.
 func (l *Logger) Print(values ...interface{}) {
-	rec := l.newRecord(values...)
-	l.origin.Check(rec.Level, rec.Message).Write(l.encoderFunc(rec)...)
+	cur := l.load()
+	cur.log(cur.newRecord(values...))
+}
+
+func (l *Logger) log(rec Record) {
+	rec.formatter = &l.formatter
+	rec.Role = l.role
+	l.classifyCancellation(&rec)
+	if l.contextlessTag && rec.SQL != "" && isContextless(rec.ctx) {
+		rec.Contextless = true
+	}
+	if l.errorRate != nil {
+		l.observeErrorRate(&rec)
+	}
+	if rec.SQL != "" {
+		if l.parser != nil {
+			l.parse(&rec)
+		}
+		l.inspectQuery(&rec)
+		if l.maxInList > 0 {
+			l.collapseInLists(&rec)
+		}
+		rec.SQL = l.formatQuery(rec.SQL)
+	}
+	if l.queryTimes && rec.SQL != "" {
+		rec.Fields = append(rec.Fields, zap.Time("sql.start", rec.Start), zap.Time("sql.end", rec.End))
+	}
+	if l.argsCount && rec.SQL != "" {
+		rec.Fields = append(rec.Fields, zap.Int("sql.args_count", len(rec.Args)))
+	}
+	if l.argsBytes && rec.SQL != "" {
+		rec.Fields = append(rec.Fields, argsBytesField(rec))
+	}
+	if l.goroutineID && rec.SQL != "" {
+		rec.Fields = append(rec.Fields, zap.Uint64("sql.goroutine", goroutineID()))
+	}
+	if l.errorTags && rec.Level >= zapcore.ErrorLevel {
+		rec.Fields = append(rec.Fields, errorTagFields(rec)...)
+	}
+	if l.cardinalityGuard != nil {
+		l.cardinalityGuard.guard(&rec)
+	}
+
+	for _, s := range l.sinks {
+		s.WriteRecord(rec)
+	}
+
+	if l.throttle(rec) && l.sample(&rec) {
+		l.write(rec)
+	}
+}
+
+// write encodes the record and writes it to zap logger.
+func (l *Logger) write(rec Record) {
+	if l.levelEnabler != nil && !l.levelEnabler.Enabled(rec.Level) {
+		return
+	}
+
+	fields := l.encode(rec)
+	if l.copyFields {
+		// The encoder func may return a slice it keeps or shares, so fields
+		// are copied before they are modified or appended to.
+		fields = append(make([]zapcore.Field, 0, len(fields)+len(l.prefixedFields)+1), fields...)
+	}
+	caller, hasCaller := zapcore.EntryCaller{}, false
+	if l.durationMillis {
+		fields = durationMillis(fields)
+	}
+	switch {
+	case l.withoutSource:
+		fields = dropField(fields, "sql.source")
+	case l.sourceAsCaller:
+		if caller, hasCaller = sourceCaller(rec.Source); hasCaller {
+			fields = dropField(fields, "sql.source")
+		}
+	}
+	if l.fieldPrefix != "" {
+		fields = prefixFields(fields, l.fieldPrefix)
+	}
+	fields = append(fields, l.prefixedFields...)
+	if l.syslogSeverity {
+		fields = append(fields, zap.Int("syslog.severity", SyslogSeverity(rec.Level)))
+	}
+	msg := rec.Message
+	if l.messageFunc != nil {
+		msg = l.messageFunc(rec)
+	}
+	ce := l.origin.Check(rec.Level, msg)
+	if ce == nil {
+		return
+	}
+	if hasCaller {
+		ce.Entry.Caller = caller
+	}
+	if l.levelCounter != nil && !rec.stats {
+		l.levelCounter.add(ce.Entry.Level, ce.Entry.Time)
+	}
+	ce.Write(fields...)
+}
+
+// encode encodes record with the encoder func. If the func panics, the panic is
+// logged as a separate error record, and the record is encoded with
+// DefaultRecordToFields instead, so that a broken encoder does not crash the
+// query goroutine.
+func (l *Logger) encode(rec Record) (fields []zapcore.Field) {
+	defer func() {
+		if p := recover(); p != nil {
+			l.origin.Error("gormzap: RecordToFields func panicked", zap.String("panic", fmt.Sprint(p)))
+			fields = DefaultRecordToFields(rec)
+		}
+	}()
+	return l.encoderFunc(rec)
+}
+
+// prepare precomputes fields shared by all records once the logger is
+// configured: prefixedFields are staticFields with fieldPrefix applied, so
+// that they are not rebuilt for every record. The prepared
+// slice is never modified, records copy it when appending. It also decides
+// if write has to copy encoded fields before changing them.
+func (l *Logger) prepare() {
+	l.formatter = l.valueFormatter()
+	l.prefixedFields = l.staticFields
+	if l.fieldPrefix != "" && len(l.staticFields) > 0 {
+		l.prefixedFields = prefixFields(append([]zapcore.Field(nil), l.staticFields...), l.fieldPrefix)
+	}
+	l.copyFields = l.durationMillis || l.withoutSource || l.sourceAsCaller ||
+		l.fieldPrefix != "" || len(l.prefixedFields) > 0 || l.syslogSeverity
+}
+
+// durationMillis replaces "sql.duration" field with "sql.duration_ms" one.
+// Fields are modified in place.
+func durationMillis(fields []zapcore.Field) []zapcore.Field {
+	for i, f := range fields {
+		if f.Key == "sql.duration" && f.Type == zapcore.DurationType {
+			fields[i] = zap.Int64("sql.duration_ms", int64(time.Duration(f.Integer)/time.Millisecond))
+		}
+	}
+	return fields
+}
+
+// prefixFields replaces default "sql." prefix of field keys with the given
+// prefix. Fields are modified in place.
+func prefixFields(fields []zapcore.Field, prefix string) []zapcore.Field {
+	for i := range fields {
+		if strings.HasPrefix(fields[i].Key, "sql.") {
+			fields[i].Key = prefix + fields[i].Key[len("sql."):]
+		}
+	}
+	return fields
 }
 
 func (l *Logger) newRecord(values ...interface{}) Record {
@@ -76,17 +590,19 @@ func (l *Logger) newRecord(values ...interface{}) Record {
 	if len(values) < 2 {
 		// Should this ever happen?
 		return Record{
-			Message: fmt.Sprint(values...),
+			Message: sprintValues(values),
 			Level:   l.level,
 		}
 	}
 
 	// Handle https://github.com/jinzhu/gorm/blob/32455088f24d6b1e9a502fb8e40fdc16139dbea8/main.go#L716
 	if len(values) == 2 {
+		err, _ := values[1].(error)
 		return Record{
-			Message: fmt.Sprintf("%v", values[1]),
-			Source:  fmt.Sprintf("%v", values[0]),
-			Level:   zapcore.ErrorLevel,
+			Message: sprint(values[1]),
+			Source:  l.source(values[0]),
+			Level:   l.mappedLevel(GormError, zapcore.ErrorLevel),
+			Err:     err,
 		}
 	}
 
@@ -98,111 +614,755 @@ func (l *Logger) newRecord(values ...interface{}) Record {
 		// See: https://github.com/jinzhu/gorm/blob/32455088f24d6b1e9a502fb8e40fdc16139dbea8/scope.go#L96
 		// If this is an error log, we set level to error.
 		// See: https://github.com/jinzhu/gorm/blob/32455088f24d6b1e9a502fb8e40fdc16139dbea8/main.go#L718
-		logLevel := l.level
-		if _, ok := values[2].(error); ok {
-			logLevel = zapcore.ErrorLevel
+		logLevel := l.mappedLevel(GormInfo, l.level)
+		err, ok := values[2].(error)
+		if ok {
+			logLevel = l.mappedLevel(GormError, zapcore.ErrorLevel)
 		}
 
 		return Record{
-			Message: fmt.Sprint(values[2:]...),
-			Source:  fmt.Sprintf("%v", values[1]),
+			Message: sprintValues(values[2:]),
+			Source:  l.source(values[1]),
 			Level:   logLevel,
+			Err:     err,
 		}
 	}
 
 	// Handle https://github.com/jinzhu/gorm/blob/32455088f24d6b1e9a502fb8e40fdc16139dbea8/main.go#L786
 	if level == "sql" {
-		return Record{
-			Message:      "gorm query",
-			Source:       fmt.Sprintf("%v", values[1]),
-			Duration:     values[2].(time.Duration),
-			SQL:          formatSQL(values[3].(string), values[4].([]interface{})),
-			RowsAffected: values[5].(int64),
-			Level:        l.level,
+		if rec, ok := l.newQueryRecord(values); ok {
+			return rec
 		}
+
+		return l.newMalformedQueryRecord(values)
 	}
 
 	// Should this ever happen?
 	return Record{
-		Message: fmt.Sprint(values[2:]...),
-		Source:  fmt.Sprintf("%v", values[1]),
+		Message: sprintValues(values[2:]),
+		Source:  l.source(values[1]),
 		Level:   l.level,
 	}
 }
 
-func formatSQL(sql string, values []interface{}) string {
-	size := len(values)
+// sprint formats v as fmt.Sprint does, without going through fmt for strings
+// and plain errors, which sources and messages logged by gorm nearly always
+// are.
+func sprint(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case fmt.Formatter:
+	case error:
+		return errorString(v)
+	}
+	return fmt.Sprint(v)
+}
 
-	replacements := make([]string, size*2)
+// errorString returns err.Error(). If it panics, e.g. for a nil pointer, err
+// is formatted with fmt.Sprint, which recovers the same way.
+func errorString(err error) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = fmt.Sprint(err)
+		}
+	}()
+	return err.Error()
+}
+
+// sprintValues formats values as fmt.Sprint does, see sprint.
+func sprintValues(values []interface{}) string {
+	if len(values) == 1 {
+		return sprint(values[0])
+	}
+	return fmt.Sprint(values...)
+}
+
+// newQueryRecord returns record for values of "sql" log. It returns false if
+// values do not match the shape gorm uses.
+func (l *Logger) newQueryRecord(values []interface{}) (Record, bool) {
+	if len(values) < 6 {
+		return Record{}, false
+	}
+
+	duration, ok := values[2].(time.Duration)
+	if !ok {
+		return Record{}, false
+	}
+	statement, ok := values[3].(string)
+	if !ok {
+		return Record{}, false
+	}
+	args, ok := parseArgs(values[4])
+	if !ok {
+		return Record{}, false
+	}
+	rowsAffected, ok := values[5].(int64)
+	if !ok {
+		return Record{}, false
+	}
+
+	return l.queryRecord(l.source(values[1]), duration, statement, args, rowsAffected), true
+}
+
+// queryRecord returns record of the query that has just completed.
+func (l *Logger) queryRecord(source string, duration time.Duration, statement string, args []interface{}, rowsAffected int64) Record {
+	// gorm logs the query right after it completes.
+	end := time.Now()
+
+	rec := Record{
+		Message:      "gorm query",
+		Source:       source,
+		Start:        end.Add(-duration),
+		End:          end,
+		Duration:     duration,
+		RowsAffected: rowsAffected,
+		Level:        l.level,
+		Statement:    statement,
+		Args:         args,
+	}
+	l.setQuerySQL(&rec)
+	return rec
+}
+
+// newMalformedQueryRecord returns best-effort record for values of "sql" log
+// that do not match the shape gorm uses, e.g. when they come from a gorm fork
+// or plugin. Each value is accepted if it has a compatible type, and the rest
+// are logged as raw "sql.values" array. The record is marked as malformed and
+// logged with at least warn level.
+func (l *Logger) newMalformedQueryRecord(values []interface{}) Record {
+	rec := Record{
+		Message: "gorm query",
+		Source:  l.source(values[1]),
+		Level:   l.level,
+	}
+
+	var raw []interface{}
+	for i, v := range values[2:] {
+		switch i {
+		case 0:
+			if d, ok := parseDuration(v); ok {
+				rec.Duration = d
+				continue
+			}
+		case 1:
+			if s, ok := v.(string); ok {
+				rec.Statement = s
+				continue
+			}
+		case 2:
+			if args, ok := parseArgs(v); ok {
+				rec.Args = args
+				continue
+			}
+		case 3:
+			if n, ok := parseInt(v); ok {
+				rec.RowsAffected = n
+				continue
+			}
+		}
+		raw = append(raw, v)
+	}
+
+	rec.Fields = []zapcore.Field{zap.Bool("sql.malformed", true)}
+	if rec.Statement != "" {
+		l.setQuerySQL(&rec)
+	}
+	if len(raw) > 0 {
+		rec.Fields = append(rec.Fields, zap.Array("sql.values", logArgs{raw, l.formatter}))
+	}
+	escalate(&rec, zapcore.WarnLevel)
+
+	return rec
+}
+
+func parseDuration(v interface{}) (time.Duration, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+// parseArgs returns bind values of the query. Maps of named args, e.g.
+// pgx.NamedArgs, passed either instead of the values or as the only value,
+// are turned into sql.NamedArg values sorted by name.
+func parseArgs(v interface{}) ([]interface{}, bool) {
+	if args, ok := v.([]interface{}); ok {
+		if len(args) == 1 {
+			if named, ok := parseNamedArgs(args[0]); ok {
+				return named, true
+			}
+		}
+		return args, true
+	}
+	return parseNamedArgs(v)
+}
+
+func parseNamedArgs(v interface{}) ([]interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		args[i] = sql.Named(k.String(), rv.MapIndex(k).Interface())
+	}
+	return args, true
+}
+
+func parseInt(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// inspectQuery applies query guardrails to the SQL record.
+func (l *Logger) inspectQuery(rec *Record) {
+	if l.slowThreshold > 0 && rec.Duration > l.slowThreshold {
+		rec.Slow = true
+		escalate(rec, zapcore.WarnLevel)
+	}
+
+	if l.rowsAffectedWarning > 0 && rec.RowsAffected > l.rowsAffectedWarning && isWriteOperation(rec.Operation()) {
+		escalate(rec, zapcore.WarnLevel)
+	}
+
+	if l.commentTags {
+		rec.Tags = parseCommentTags(rec.Statement)
+	}
+
+	if l.queryHash {
+		rec.QueryHash = hashNormalized(rec.normalized())
+	}
+
+	if l.ddl && isDDLOperation(rec.Operation()) {
+		rec.DDL = true
+		escalate(rec, l.ddlLevel)
+	}
+
+	l.applyRules(rec)
+
+	if rec.Slow && l.explainDB != nil {
+		l.explain(rec)
+	}
+}
+
+// escalate raises record level to the given one, but never lowers it.
+func escalate(rec *Record, level zapcore.Level) {
+	if rec.Level < level {
+		rec.Level = level
+	}
+}
+
+// statementKeyword returns the first keyword of the SQL statement in upper
+// case, skipping leading whitespace and opening parentheses.
+func statementKeyword(sql string) string {
+	sql = strings.TrimLeftFunc(sql, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '('
+	})
+	end := strings.IndexFunc(sql, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end == -1 {
+		end = len(sql)
+	}
+	return strings.ToUpper(sql[:end])
+}
+
+// tableRegexp matches the first table name the statement refers to.
+var tableRegexp = regexp.MustCompile("(?i)\\b(?:FROM|INTO|UPDATE|JOIN|TABLE)\\s+(?:IF\\s+(?:NOT\\s+)?EXISTS\\s+)?([`\"\\w.]+)")
+
+// statementTable returns name of the first table the statement refers to,
+// without quotes, or empty string if it is not found.
+func statementTable(sql string) string {
+	m := tableRegexp.FindStringSubmatch(sql)
+	if m == nil {
+		return ""
+	}
+	return strings.NewReplacer("`", "", `"`, "").Replace(m[1])
+}
+
+func isWriteOperation(op string) bool {
+	switch op {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
+func isDDLOperation(op string) bool {
+	switch op {
+	case "CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME":
+		return true
+	}
+	return false
+}
+
+// setQuerySQL sets SQL query to log from the record statement and args, and
+// attaches format error, if any.
+func (l *Logger) setQuerySQL(rec *Record) {
+	if l.withoutValues {
+		rec.SQL, rec.Oversize = l.formatter.truncate(rec.Statement)
+		return
+	}
+
+	var err error
+	rec.SQL, rec.Oversize, err = formatSQL(rec.Statement, rec.Args, l.formatter)
+	if err != nil {
+		rec.Fields = append(rec.Fields, zap.String("sql.format_error", err.Error()))
+	}
+	if !rec.Oversize && l.maxFormattedArgs > 0 && len(rec.Args) > l.maxFormattedArgs {
+		rec.Fields = append(rec.Fields, zap.Int("sql.args_omitted", len(rec.Args)-l.maxFormattedArgs))
+	}
+}
+
+func (l *Logger) valueFormatter() valueFormatter {
+	return valueFormatter{
+		maxLen:           l.maxValueLen,
+		bytes:            l.bytesPolicy,
+		keepPlaceholders: l.keepPlaceholders,
+		maxArgs:          l.maxFormattedArgs,
+		maxBytes:         l.maxQueryBytes,
+	}
+}
 
-	var indexFunc func(int) string
-	if strings.Contains(sql, "$1") {
-		indexFunc = formatNumbered
-	} else {
-		indexFunc = formatQuestioned
+// formatSQL interpolates values into sql. If the formatter keeps placeholders,
+// it also returns an error describing values that could not be rendered. If
+// the query exceeds the formatter size limit, sql is returned as is, or
+// truncated if it exceeds the limit itself, and oversize is true.
+func formatSQL(sql string, values []interface{}, f valueFormatter) (formatted string, oversize bool, err error) {
+	if truncated, ok := f.truncate(sql); ok {
+		return truncated, true, nil
 	}
 
-	for i := size - 1; i >= 0; i-- {
-		replacements[(size-i-1)*2] = indexFunc(i)
-		replacements[(size-i-1)*2+1] = formatValue(values[i])
+	p := interpolator{f: f, values: values}
+	p.b.Grow(len(sql) + len(values)*8)
+
+	switch {
+	case strings.Contains(sql, "$1"):
+		p.numbered(sql)
+	case strings.IndexByte(sql, '{') != -1 && hasNamedArgs(values):
+		p.named(sql, bracedParamRegexp, bracedParamName)
+	case strings.IndexByte(sql, '@') != -1 && hasNamedArgs(values):
+		p.named(sql, atParamRegexp, atParamName)
+	default:
+		p.questioned(sql)
 	}
 
-	r := strings.NewReplacer(replacements...)
-	return r.Replace(sql)
+	if p.oversize {
+		return sql, true, nil
+	}
+	return p.b.String(), false, p.err()
 }
 
-func formatNumbered(index int) string {
-	return fmt.Sprintf("$%d", index+1)
+// truncatedMarker is appended to statements truncated to the formatter size
+// limit.
+const truncatedMarker = "...<truncated>"
+
+// truncate returns sql cut to the formatter size limit with truncatedMarker
+// appended, and true, if sql exceeds the limit. Otherwise sql is returned as
+// is.
+func (f valueFormatter) truncate(sql string) (string, bool) {
+	if f.maxBytes <= 0 || len(sql) <= f.maxBytes {
+		return sql, false
+	}
+	n := f.maxBytes
+	for n > 0 && !utf8.RuneStart(sql[n]) {
+		n--
+	}
+	return sql[:n] + truncatedMarker, true
 }
 
-func formatQuestioned(index int) string {
-	return "?"
+// interpolator writes the statement with placeholders replaced by values in
+// a single pass, rendering each value when its placeholder is reached.
+// Placeholders inside literals and comments are left as is.
+type interpolator struct {
+	f      valueFormatter
+	values []interface{}
+	b      strings.Builder
+
+	// scratch is a buffer values are rendered into before they are written.
+	scratch []byte
+	// errs holds render errors by value index, if the formatter keeps
+	// placeholders.
+	errs map[int]error
+	// oversize shows if the query has exceeded the formatter size limit, so
+	// that the rest of values are not rendered.
+	oversize bool
 }
 
-func formatValue(value interface{}) string {
-	indirectValue := reflect.Indirect(reflect.ValueOf(value))
-	if !indirectValue.IsValid() {
-		return "NULL"
+// writeValue writes value i. If the value cannot be rendered, it writes
+// the placeholder if the formatter keeps placeholders, and NULL otherwise.
+// Values beyond the formatter limit are not rendered at all, and their
+// placeholders are written as is.
+func (p *interpolator) writeValue(i int, placeholder string) {
+	if p.oversize || (p.f.maxArgs > 0 && i >= p.f.maxArgs) {
+		p.b.WriteString(placeholder)
+		return
 	}
 
-	value = indirectValue.Interface()
+	var err error
+	p.scratch, err = p.f.appendValue(p.scratch[:0], p.values[i])
+	if err == nil {
+		if p.f.maxBytes > 0 && p.b.Len()+len(p.scratch) > p.f.maxBytes {
+			p.oversize = true
+			return
+		}
+		p.b.Write(p.scratch)
+		return
+	}
+	if !p.f.keepPlaceholders {
+		p.b.WriteString("NULL")
+		return
+	}
+	if p.errs == nil {
+		p.errs = make(map[int]error)
+	}
+	p.errs[i] = err
+	p.b.WriteString(placeholder)
+}
+
+// err returns an error describing values that could not be rendered, in
+// order of their indexes.
+func (p *interpolator) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	errs := make([]string, 0, len(p.errs))
+	for i := range p.values {
+		if err, ok := p.errs[i]; ok {
+			errs = append(errs, fmt.Sprintf("arg %d: %v", i+1, err))
+		}
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// numbered replaces $N placeholders with values.
+func (p *interpolator) numbered(sql string) {
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		if kind != tokenText {
+			p.b.WriteString(tok)
+			return
+		}
+		for {
+			i := strings.IndexByte(tok, '$')
+			if i == -1 {
+				p.b.WriteString(tok)
+				return
+			}
+			p.b.WriteString(tok[:i])
+
+			j := i + 1
+			for j < len(tok) && tok[j] >= '0' && tok[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(tok[i+1 : j])
+			if err == nil && n >= 1 && n <= len(p.values) {
+				p.writeValue(n-1, tok[i:j])
+			} else {
+				p.b.WriteString(tok[i:j])
+			}
+			tok = tok[j:]
+		}
+	})
+}
+
+// questioned replaces ? placeholders with values in order. Doubled ??
+// placeholders, which some MySQL query builders use for identifiers, are
+// left as is.
+func (p *interpolator) questioned(sql string) {
+	n := 0
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		if kind != tokenText {
+			p.b.WriteString(tok)
+			return
+		}
+		for {
+			i := strings.IndexByte(tok, '?')
+			if i == -1 {
+				p.b.WriteString(tok)
+				return
+			}
+			p.b.WriteString(tok[:i])
+
+			switch {
+			case i+1 < len(tok) && tok[i+1] == '?':
+				p.b.WriteString("??")
+				i++
+			case n < len(p.values):
+				p.writeValue(n, "?")
+				n++
+			default:
+				p.b.WriteByte('?')
+				n++
+			}
+			tok = tok[i+1:]
+		}
+	})
+}
+
+// bracedParamRegexp matches ClickHouse {name:Type} query parameters.
+var bracedParamRegexp = regexp.MustCompile(`\{[A-Za-z_][A-Za-z0-9_]*:[^{}]+\}`)
+
+func bracedParamName(p string) string {
+	return p[1:strings.IndexByte(p, ':')]
+}
+
+// atParamRegexp matches @name query parameters, used by pgx named args and
+// SQL Server. MySQL @@system variables are matched too, to be skipped.
+var atParamRegexp = regexp.MustCompile(`@@?[A-Za-z_][A-Za-z0-9_]*`)
+
+func atParamName(p string) string {
+	if strings.HasPrefix(p, "@@") {
+		return ""
+	}
+	return p[1:]
+}
+
+// named replaces query parameters matched by re with values of sql.NamedArg
+// args of the same name. Parameters without a matching arg are left as is.
+func (p *interpolator) named(query string, re *regexp.Regexp, name func(p string) string) {
+	named := make(map[string]int, len(p.values))
+	for i, a := range p.values {
+		if na, ok := a.(sql.NamedArg); ok {
+			named[na.Name] = i
+		}
+	}
+
+	lexSQL(query, func(kind tokenKind, tok string) {
+		if kind != tokenText {
+			p.b.WriteString(tok)
+			return
+		}
+		last := 0
+		for _, m := range re.FindAllStringIndex(tok, -1) {
+			p.b.WriteString(tok[last:m[0]])
+			param := tok[m[0]:m[1]]
+			if i, ok := named[name(param)]; ok {
+				p.writeValue(i, param)
+			} else {
+				p.b.WriteString(param)
+			}
+			last = m[1]
+		}
+		p.b.WriteString(tok[last:])
+	})
+}
+
+func hasNamedArgs(args []interface{}) bool {
+	for _, a := range args {
+		if _, ok := a.(sql.NamedArg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// valueFormatter formats bind values for interpolation into the logged query.
+type valueFormatter struct {
+	maxLen           int
+	bytes            BytesPolicy
+	keepPlaceholders bool
+	// maxArgs is the number of values to render, or zero for all.
+	maxArgs int
+	// maxBytes is the maximum size of the formatted query, or zero for no
+	// limit.
+	maxBytes int
+}
+
+// appendValue appends formatted value to dst and returns the extended buffer.
+// If the value cannot be rendered, dst is returned unchanged with an error.
+func (f valueFormatter) appendValue(dst []byte, value interface{}) ([]byte, error) {
+	// Dereference pointers only, as reflecting other values back into
+	// interface would allocate.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return append(dst, "NULL"...), nil
+		}
+		value = rv.Elem().Interface()
+	}
+	if value == nil {
+		return append(dst, "NULL"...), nil
+	}
 
 	switch v := value.(type) {
 	case time.Time:
-		return fmt.Sprintf("'%v'", v.Format("2006-01-02 15:04:05"))
+		dst = append(dst, '\'')
+		dst = v.AppendFormat(dst, "2006-01-02 15:04:05")
+		return append(dst, '\''), nil
 	case []byte:
+		if !f.isTextBytes(v) {
+			return append(dst, "'<binary>'"...), nil
+		}
+		if f.maxLen > 0 && len(v)+2 > f.maxLen {
+			// Escaping only makes the value longer, so it is redacted anyway.
+			return append(dst, "'<redacted>'"...), nil
+		}
 		s := string(v)
-		if isPrintable(s) {
-			return redactLong(fmt.Sprintf("'%s'", s))
+		if f.bytes == BytesUTF8Escaped {
+			s = escapeControl(s)
 		}
-		return "'<binary>'"
-	case int, int8, int16, int32, int64,
-		uint, uint8, uint16, uint32, uint64:
-		return fmt.Sprintf("%d", v)
+		return f.appendQuoted(dst, s), nil
+	case string:
+		return f.appendQuoted(dst, v), nil
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(dst, v, 10), nil
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(dst, v, 10), nil
+	case sql.NamedArg:
+		return f.appendValue(dst, v.Value)
 	case driver.Valuer:
-		if dv, err := v.Value(); err == nil && dv != nil {
-			return formatValue(dv)
+		dv, err := driverValue(v)
+		if err != nil {
+			return dst, err
+		}
+		return f.appendValue(dst, dv)
+	default:
+		return f.appendQuoted(dst, fmt.Sprintf("%v", value)), nil
+	}
+}
+
+// driverValue returns value of v, turning a panic into an error.
+func driverValue(v driver.Valuer) (dv driver.Value, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("Value panicked: %v", p)
 		}
-		return "NULL"
+	}()
+	return v.Value()
+}
+
+// appendQuoted appends s in single quotes, or '<redacted>' if it is too long.
+func (f valueFormatter) appendQuoted(dst []byte, s string) []byte {
+	if f.maxLen > 0 && len(s)+2 > f.maxLen {
+		return append(dst, "'<redacted>'"...)
+	}
+	dst = append(dst, '\'')
+	dst = append(dst, s...)
+	return append(dst, '\'')
+}
+
+// isText reports whether []byte value can be logged as text according to
+// the bytes policy.
+func (f valueFormatter) isText(s string) bool {
+	switch f.bytes {
+	case BytesUTF8, BytesUTF8Escaped:
+		return utf8.ValidString(scanPrefix(s))
 	default:
-		return redactLong(fmt.Sprintf("'%v'", value))
+		return isPrintable(s)
+	}
+}
+
+// isTextBytes is like isText, but converts only the inspected prefix of v to
+// string.
+func (f valueFormatter) isTextBytes(v []byte) bool {
+	if n := printableScanLimit + utf8.UTFMax; len(v) > n {
+		v = v[:n]
+	}
+	return f.isText(string(v))
+}
+
+// printableScanLimit is the maximum number of bytes of a value inspected to
+// decide if it is text or binary data.
+const printableScanLimit = 4096
+
+// scanPrefix returns the prefix of s to inspect, cut at rune boundary.
+func scanPrefix(s string) string {
+	if len(s) <= printableScanLimit {
+		return s
+	}
+	n := printableScanLimit
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
 	}
+	return s[:n]
 }
 
+// isPrintable reports whether s consists of printable characters only.
+// Only the first printableScanLimit bytes are inspected.
 func isPrintable(s string) bool {
-	for _, r := range s {
-		if !unicode.IsPrint(r) {
+	s = scanPrefix(s)
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			// Fast path for ASCII.
+			if c < ' ' || c == 0x7f {
+				return false
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 || !unicode.IsPrint(r) {
 			return false
 		}
+		i += size
 	}
 	return true
 }
 
-func redactLong(s string) string {
-	if len(s) > maxLen {
-		return "'<redacted>'"
+// escapeControl returns s with control characters replaced with Go escape
+// sequences.
+func escapeControl(s string) string {
+	if strings.IndexFunc(s, unicode.IsControl) == -1 {
+		return s
 	}
-	return s
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r) && r < utf8.RuneSelf:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
 }
 
+// maxLen is the default maximum length of interpolated values.
 const maxLen = 255
+
+// maxQueryBytes is the default maximum size of the formatted query.
+const maxQueryBytes = 1 << 20
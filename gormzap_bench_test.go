@@ -1,8 +1,15 @@
 package gormzap_test
 
 import (
+	"errors"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func BenchmarkLogger_Print(b *testing.B) {
@@ -19,3 +26,216 @@ func BenchmarkLogger_Print(b *testing.B) {
 		)
 	}
 }
+
+func BenchmarkLogger_Print_bulkInsert(b *testing.B) {
+	l, _ := logger()
+
+	args := make([]interface{}, 0, 300)
+	for i := 0; i < 100; i++ {
+		args = append(args, i, int64(i*1000), uint32(i))
+	}
+	sql := "INSERT INTO test (a, b, c) VALUES " + strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", 100), ", ")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			sql,
+			args,
+			int64(100),
+		)
+	}
+}
+
+func BenchmarkLogger_Print_staticFields(b *testing.B) {
+	l := discardLogger(
+		gormzap.WithFieldPrefix("db."),
+		gormzap.WithDatabaseInfo("app", "db.example.com", "app"),
+		gormzap.WithServiceInfo("app", "1.2.3"),
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		printQuery(l)
+	}
+}
+
+func BenchmarkLogger_Print_Parallel(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []gormzap.LoggerOption
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "all inspections",
+			opts: []gormzap.LoggerOption{
+				gormzap.WithSlowThreshold(time.Second),
+				gormzap.WithRowsAffectedWarning(1000),
+				gormzap.WithDDLLevel(zap.WarnLevel),
+				gormzap.WithQueryHash(),
+				gormzap.WithCommentTags(),
+				gormzap.WithRules(
+					gormzap.SelectStarRule(zap.InfoLevel),
+					gormzap.UnboundedWriteRule(zap.WarnLevel),
+					gormzap.LeadingWildcardLikeRule(zap.WarnLevel),
+				),
+			},
+		},
+		{
+			name: "sink",
+			opts: []gormzap.LoggerOption{
+				gormzap.WithSinks(gormzap.RecordSinkFunc(func(gormzap.Record) {})),
+			},
+		},
+		{
+			name: "counters",
+			opts: []gormzap.LoggerOption{
+				gormzap.WithLevelCounter(gormzap.NewLevelCounter()),
+				gormzap.WithErrorRateEscalation(0.5, time.Minute, zap.InfoLevel),
+				gormzap.WithSinks(gormzap.NewLatencyTracker()),
+			},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			l := discardLogger(bm.opts...)
+
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					printQuery(l)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkLogger_Print_ParallelReconfigure(b *testing.B) {
+	l := discardLogger()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		cfg := gormzap.Config{Interpolate: true}
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				l.Reconfigure(cfg)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			printQuery(l)
+		}
+	})
+}
+
+func BenchmarkMigration_Print_Parallel(b *testing.B) {
+	m := discardLogger().StartMigration("bench")
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				"CREATE TABLE test (id int)",
+				[]interface{}{},
+				int64(0),
+			)
+		}
+	})
+}
+
+func printQuery(l *gormzap.Logger) {
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT id FROM test WHERE id = $1 AND name LIKE $2 /* job=bench */",
+		[]interface{}{42, "foo%"},
+		int64(1),
+	)
+}
+
+// discardLogger returns logger which encodes records but discards them, and
+// is safe for concurrent use, unlike the one writing to zaptest.Buffer.
+func discardLogger(opts ...gormzap.LoggerOption) *gormzap.Logger {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(ioutil.Discard), zapcore.DebugLevel)
+	return gormzap.NewWithCore(core, opts...)
+}
+
+// recordShapes are the shapes of values gorm logs, with allocation budgets
+// of logging them with discardLogger. Budgets are enforced by
+// TestLogger_Print_allocs, so raise them only with a good reason.
+var recordShapes = []struct {
+	name   string
+	values []interface{}
+	allocs float64
+}{
+	{
+		name:   "sql",
+		values: []interface{}{"sql", "/some/file.go:34", time.Millisecond * 5, "SELECT * FROM test WHERE id = $1", []interface{}{42}, int64(1)},
+		allocs: 3,
+	},
+	{
+		name:   "log",
+		values: []interface{}{"log", "/some/file.go:34", "some log message"},
+		allocs: 1,
+	},
+	{
+		name:   "error",
+		values: []interface{}{"/some/file.go:34", errors.New("some serious error!")},
+		allocs: 1,
+	},
+	{
+		name:   "malformed",
+		values: []interface{}{"sql", "/some/file.go:34", "5ms", "SELECT 1", []interface{}{}, 1},
+		allocs: 4,
+	},
+}
+
+func BenchmarkLogger_Print_shapes(b *testing.B) {
+	for _, rs := range recordShapes {
+		b.Run(rs.name, func(b *testing.B) {
+			l := discardLogger()
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Print(rs.values...)
+			}
+		})
+	}
+}
+
+func TestLogger_Print_allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("Allocation budgets do not hold with the race detector")
+	}
+
+	for _, rs := range recordShapes {
+		t.Run(rs.name, func(t *testing.T) {
+			l := discardLogger()
+
+			allocs := testing.AllocsPerRun(100, func() {
+				l.Print(rs.values...)
+			})
+			if allocs > rs.allocs {
+				t.Fatalf("Expected at most %v allocations but got %v", rs.allocs, allocs)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package gormzap_test
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -58,6 +62,42 @@ func ExampleWithRecordToFields() {
 	// {"level":"debug","msg":"gorm query","caller":"/foo/bar.go","duration_ms":200,"query":"SELECT * FROM foo WHERE id = 123","rows_affected":2}
 }
 
+func ExampleParameterizedRecordToFields() {
+	z := zap.NewExample()
+
+	l := gormzap.New(z, gormzap.WithRecordToFields(gormzap.ParameterizedRecordToFields))
+
+	l.Print(
+		"sql",
+		"/foo/bar.go",
+		time.Second*2,
+		"SELECT * FROM foo WHERE id = ? AND name = ?",
+		[]interface{}{123, "bar"},
+		int64(2),
+	)
+
+	// Output:
+	// {"level":"debug","msg":"gorm query","sql.source":"/foo/bar.go","sql.duration":"2s","sql.statement":"SELECT * FROM foo WHERE id = ? AND name = ?","sql.args":[123,"bar"],"sql.args_count":2,"sql.rows_affected":2}
+}
+
+func ExampleTemplateRecordToFields() {
+	z := zap.NewExample()
+
+	l := gormzap.New(z, gormzap.WithRecordToFields(gormzap.TemplateRecordToFields))
+
+	l.Print(
+		"sql",
+		"/foo/bar.go",
+		time.Second*2,
+		"SELECT * FROM foo WHERE id = ? AND name = ?",
+		[]interface{}{123, "bar"},
+		int64(2),
+	)
+
+	// Output:
+	// {"level":"debug","msg":"gorm query","sql.source":"/foo/bar.go","sql.duration":"2s","sql.template":"SELECT * FROM foo WHERE id = ? AND name = ?","sql.query":"SELECT * FROM foo WHERE id = 123 AND name = 'bar'","sql.rows_affected":2}
+}
+
 func TestLogger_Print(t *testing.T) {
 	t.Run("log with values < 2", func(t *testing.T) {
 		l, buf := logger()
@@ -83,6 +123,18 @@ func TestLogger_Print(t *testing.T) {
 		}
 	})
 
+	t.Run("log with values = 2 (nil error)", func(t *testing.T) {
+		l, buf := logger()
+
+		l.Print("/some/file.go:32", (*myError)(nil))
+		expected := `{"level":"error","msg":"<nil>","sql.source":"/some/file.go:32"}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
 	t.Run("log with level = log (error)", func(t *testing.T) {
 		l, buf := logger()
 
@@ -134,11 +186,736 @@ func TestLogger_Print(t *testing.T) {
 			t.Fatalf("Expected %s but got %s", expected, actual)
 		}
 	})
+
+	t.Run("log with level = sql (malformed)", func(t *testing.T) {
+		l, buf := logger()
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			"5ms",
+			"SELECT * FROM test WHERE id = $1",
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE id = $1","sql.rows_affected":0,"sql.malformed":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("log with level = sql (malformed, differently typed)", func(t *testing.T) {
+		l, buf := logger()
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM test WHERE id = $1",
+			42,
+			1,
+			"extra",
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE id = $1","sql.rows_affected":1,"sql.malformed":true,"sql.values":[42,"extra"]}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("log with level = sql (malformed, no statement)", func(t *testing.T) {
+		l, buf := logger()
+
+		l.Print("sql", "/some/file.go:34", struct{}{})
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.malformed":true,"sql.values":["{}"]}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+type myError struct {
+	msg string
+}
+
+func (e *myError) Error() string {
+	return e.msg
+}
+
+func TestLogger_Print_placeholders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sql      string
+		args     []interface{}
+		expected string
+	}{
+		{
+			name:     "in order",
+			sql:      "SELECT * FROM test WHERE a = ? AND b = ?",
+			args:     []interface{}{1, "foo"},
+			expected: "SELECT * FROM test WHERE a = 1 AND b = 'foo'",
+		},
+		{
+			name:     "identifier placeholder",
+			sql:      "SELECT ?? FROM test WHERE id = ?",
+			args:     []interface{}{42},
+			expected: "SELECT ?? FROM test WHERE id = 42",
+		},
+		{
+			name:     "in literals and comments",
+			sql:      "SELECT '?' FROM test /* ? */ WHERE id = ?",
+			args:     []interface{}{42},
+			expected: "SELECT '?' FROM test /* ? */ WHERE id = 42",
+		},
+		{
+			name:     "more placeholders than args",
+			sql:      "SELECT * FROM test WHERE a = ? AND b = ?",
+			args:     []interface{}{1},
+			expected: "SELECT * FROM test WHERE a = 1 AND b = ?",
+		},
+		{
+			name:     "numbered",
+			sql:      "SELECT * FROM test WHERE a = $2 AND b = $1 AND c = $1",
+			args:     []interface{}{"foo", 1},
+			expected: "SELECT * FROM test WHERE a = 1 AND b = 'foo' AND c = 'foo'",
+		},
+		{
+			name:     "numbered in literals and out of range",
+			sql:      "SELECT '$1' FROM test WHERE a = $1 AND b = $12",
+			args:     []interface{}{1},
+			expected: "SELECT '$1' FROM test WHERE a = 1 AND b = $12",
+		},
+		{
+			name:     "clickhouse parameters",
+			sql:      "SELECT * FROM test WHERE a = {a:UInt32} AND b = {b:String} AND c = {c:String}",
+			args:     []interface{}{sql.Named("b", "foo"), sql.Named("a", 1)},
+			expected: "SELECT * FROM test WHERE a = 1 AND b = 'foo' AND c = {c:String}",
+		},
+		{
+			name:     "named args with ?",
+			sql:      "SELECT * FROM test WHERE a = ?",
+			args:     []interface{}{sql.Named("a", 1)},
+			expected: "SELECT * FROM test WHERE a = 1",
+		},
+		{
+			name:     "at parameters",
+			sql:      "SELECT * FROM test WHERE a = @a AND b = @b AND c = @c AND d = @@d",
+			args:     []interface{}{map[string]interface{}{"b": "foo", "a": 1, "d": 2}},
+			expected: "SELECT * FROM test WHERE a = 1 AND b = 'foo' AND c = @c AND d = @@d",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, records := observer()
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, tc.sql, tc.args, int64(1))
+
+			actual := records.AllRecords()[0].SQL
+			if actual != tc.expected {
+				t.Fatalf("Expected %q but got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLogger_Print_namedArgsMap(t *testing.T) {
+	type namedArgs map[string]interface{}
+
+	l, records := observer()
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = @a AND b = @b",
+		namedArgs{"b": "foo", "a": 1},
+		int64(1),
+	)
+
+	rec := records.AllRecords()[0]
+	expectedSQL := "SELECT * FROM test WHERE a = 1 AND b = 'foo'"
+	if rec.SQL != expectedSQL {
+		t.Fatalf("Expected %q but got %q", expectedSQL, rec.SQL)
+	}
+	expectedArgs := []interface{}{sql.Named("a", 1), sql.Named("b", "foo")}
+	if !reflect.DeepEqual(rec.Args, expectedArgs) {
+		t.Fatalf("Expected args %v but got %v", expectedArgs, rec.Args)
+	}
+	if rec.Level != zapcore.DebugLevel {
+		t.Fatalf("Expected debug level but got %v", rec.Level)
+	}
+}
+
+func TestWithRecordToFields(t *testing.T) {
+	t.Run("panicking encoder", func(t *testing.T) {
+		l, buf := logger(gormzap.WithRecordToFields(func(r gormzap.Record) []zapcore.Field {
+			panic("oops")
+		}))
+
+		l.Print("log", "/some/file.go:33", "foo")
+		expected := []string{
+			`{"level":"error","msg":"gormzap: RecordToFields func panicked","panic":"oops"}`,
+			`{"level":"debug","msg":"foo","sql.source":"/some/file.go:33"}`,
+		}
+
+		for i, e := range expected {
+			if actual := buf.Lines()[i]; actual != e {
+				t.Fatalf("Expected %s but got %s", e, actual)
+			}
+		}
+	})
+
+	t.Run("shared fields", func(t *testing.T) {
+		shared := make([]zapcore.Field, 2, 4)
+		shared[0] = zap.String("sql.source", "/some/file.go:33")
+		shared[1] = zap.String("sql.query", "SELECT 1")
+
+		l, buf := logger(
+			gormzap.WithRecordToFields(func(r gormzap.Record) []zapcore.Field {
+				return shared
+			}),
+			gormzap.WithoutSource(),
+			gormzap.WithFieldPrefix("db."),
+			gormzap.WithDatabaseInfo("main", "", ""),
+		)
+
+		l.Print("log", "/some/file.go:33", "foo")
+		l.Print("log", "/some/file.go:33", "foo")
+
+		expected := `{"level":"debug","msg":"foo","db.query":"SELECT 1","db.database":"main"}`
+		for _, actual := range buf.Lines() {
+			if actual != expected {
+				t.Fatalf("Expected %s but got %s", expected, actual)
+			}
+		}
+		if shared[0].Key != "sql.source" || shared[1].Key != "sql.query" || shared[:3][2].Key != "" {
+			t.Fatalf("Expected encoder fields to be left intact but got %v", shared[:3])
+		}
+	})
+}
+
+func TestWithBytesPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   gormzap.BytesPolicy
+		value    []byte
+		expected string
+	}{
+		{
+			name:     "printable, text",
+			policy:   gormzap.BytesPrintable,
+			value:    []byte("привет"),
+			expected: `INSERT INTO test (data) VALUES ('привет')`,
+		},
+		{
+			name:     "printable, multiline text",
+			policy:   gormzap.BytesPrintable,
+			value:    []byte("foo\nbar"),
+			expected: `INSERT INTO test (data) VALUES ('<binary>')`,
+		},
+		{
+			name:     "utf8, multiline text",
+			policy:   gormzap.BytesUTF8,
+			value:    []byte("foo\nbar"),
+			expected: "INSERT INTO test (data) VALUES ('foo\nbar')",
+		},
+		{
+			name:     "utf8 escaped, multiline text",
+			policy:   gormzap.BytesUTF8Escaped,
+			value:    []byte("foo\nbar\tbaz\x00\u0085"),
+			expected: `INSERT INTO test (data) VALUES ('foo\nbar\tbaz\x00\u0085')`,
+		},
+		{
+			name:     "utf8, binary",
+			policy:   gormzap.BytesUTF8,
+			value:    []byte{0xff, 0xfe, 0x00},
+			expected: `INSERT INTO test (data) VALUES ('<binary>')`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, records := observer(gormzap.WithBytesPolicy(tc.policy))
+
+			l.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				"INSERT INTO test (data) VALUES ($1)",
+				[]interface{}{tc.value},
+				int64(1),
+			)
+
+			actual := records.AllRecords()[0].SQL
+			if actual != tc.expected {
+				t.Fatalf("Expected %q but got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("boom")
+}
+
+func TestWithKeepPlaceholders(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          []gormzap.LoggerOption
+		expectedSQL   string
+		expectedError string
+	}{
+		{
+			name:        "default",
+			expectedSQL: `UPDATE test SET data = NULL WHERE id = 42`,
+		},
+		{
+			name:          "keep placeholders",
+			opts:          []gormzap.LoggerOption{gormzap.WithKeepPlaceholders()},
+			expectedSQL:   `UPDATE test SET data = $1 WHERE id = 42`,
+			expectedError: "arg 1: boom",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, records := observer(tc.opts...)
+
+			l.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				"UPDATE test SET data = $1 WHERE id = $2",
+				[]interface{}{failingValuer{}, 42},
+				int64(1),
+			)
+
+			rec := records.AllRecords()[0]
+			if rec.SQL != tc.expectedSQL {
+				t.Fatalf("Expected %q but got %q", tc.expectedSQL, rec.SQL)
+			}
+
+			var actualError string
+			for _, f := range rec.Fields {
+				if f.Key == "sql.format_error" {
+					actualError = f.String
+				}
+			}
+			if actualError != tc.expectedError {
+				t.Fatalf("Expected format error %q but got %q", tc.expectedError, actualError)
+			}
+		})
+	}
+}
+
+type panickingValuer struct{}
+
+func (panickingValuer) Value() (driver.Value, error) {
+	panic("oops")
+}
+
+func TestParameterizedRecordToFields_args(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithRecordToFields(gormzap.ParameterizedRecordToFields),
+		gormzap.WithMaxValueLen(4),
+	)
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = $1 AND b = $2 AND c = $3",
+		[]interface{}{"foo", "foobar", panickingValuer{}},
+		int64(1),
+	)
+
+	expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.statement":"SELECT * FROM test WHERE a = $1 AND b = $2 AND c = $3","sql.args":["foo","<redacted>",null],"sql.args_count":3,"sql.rows_affected":1}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithMaxFormattedArgs(t *testing.T) {
+	l, buf := logger(gormzap.WithMaxFormattedArgs(2))
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"INSERT INTO test (a, b) VALUES (?, ?), (?, ?)",
+		[]interface{}{1, "foo", 2, "bar"},
+		int64(2),
+	)
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = $1",
+		[]interface{}{1},
+		int64(1),
+	)
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"INSERT INTO test (a, b) VALUES (1, 'foo'), (?, ?)","sql.rows_affected":2,"sql.args_omitted":2}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE a = 1","sql.rows_affected":1}`,
+	}
+	for i, e := range expected {
+		if actual := buf.Lines()[i]; actual != e {
+			t.Fatalf("Expected %s but got %s", e, actual)
+		}
+	}
+}
+
+func TestWithMaxQueryBytes(t *testing.T) {
+	l, buf := logger(gormzap.WithMaxQueryBytes(45))
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = $1",
+		[]interface{}{"foo"},
+		int64(1),
+	)
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = $1 AND b = $2",
+		[]interface{}{"foo", "bar"},
+		int64(1),
+	)
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE a = $1 AND b = $2 AND c = $3",
+		[]interface{}{"foo", "bar", "baz"},
+		int64(1),
+	)
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE a = 'foo'","sql.rows_affected":1}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE a = $1 AND b = $2","sql.rows_affected":1,"sql.oversize":true}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE a = $1 AND b = $2 AN...<truncated>","sql.rows_affected":1,"sql.oversize":true}`,
+	}
+	for i, e := range expected {
+		if actual := buf.Lines()[i]; actual != e {
+			t.Fatalf("Expected %s but got %s", e, actual)
+		}
+	}
+}
+
+func TestWithRowsAffectedWarning(t *testing.T) {
+	t.Run("write above threshold", func(t *testing.T) {
+		l, buf := logger(gormzap.WithRowsAffectedWarning(100))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"DELETE FROM test WHERE created_at < $1",
+			[]interface{}{"2018-01-01"},
+			int64(101),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"DELETE FROM test WHERE created_at < '2018-01-01'","sql.rows_affected":101}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("write within threshold", func(t *testing.T) {
+		l, buf := logger(gormzap.WithRowsAffectedWarning(100))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"UPDATE test SET name = $1",
+			[]interface{}{"foo"},
+			int64(100),
+		)
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"UPDATE test SET name = 'foo'","sql.rows_affected":100}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("read above threshold", func(t *testing.T) {
+		l, buf := logger(gormzap.WithRowsAffectedWarning(100))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM test",
+			[]interface{}{},
+			int64(500),
+		)
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test","sql.rows_affected":500}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func TestWithDDLLevel(t *testing.T) {
+	t.Run("ddl", func(t *testing.T) {
+		l, buf := logger(gormzap.WithDDLLevel(zap.WarnLevel))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"ALTER TABLE test ADD COLUMN name text",
+			[]interface{}{},
+			int64(0),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"ALTER TABLE test ADD COLUMN name text","sql.rows_affected":0,"sql.ddl":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("dml", func(t *testing.T) {
+		l, buf := logger(gormzap.WithDDLLevel(zap.WarnLevel))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"INSERT INTO test (name) VALUES ($1)",
+			[]interface{}{"create"},
+			int64(1),
+		)
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"INSERT INTO test (name) VALUES ('create')","sql.rows_affected":1}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func TestWithQueryTimes(t *testing.T) {
+	l, records := observer(gormzap.WithQueryTimes())
+
+	before := time.Now()
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	after := time.Now()
+
+	rec := records.AllRecords()[0]
+	if rec.End.Before(before) || rec.End.After(after) {
+		t.Fatalf("Expected end time between %s and %s but got %s", before, after, rec.End)
+	}
+	if d := rec.End.Sub(rec.Start); d != time.Millisecond*5 {
+		t.Fatalf("Expected 5ms between start and end but got %s", d)
+	}
+
+	if len(rec.Fields) != 2 || rec.Fields[0].Key != "sql.start" || rec.Fields[1].Key != "sql.end" {
+		t.Fatalf("Expected sql.start and sql.end fields but got %v", rec.Fields)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("nil origin", func(t *testing.T) {
+		z, buf := zapLogger()
+		defer zap.ReplaceGlobals(z)()
+
+		l := gormzap.New(nil)
+
+		l.Print("log", "/some/file.go:33", "foo")
+		expected := `{"level":"debug","msg":"foo","sql.source":"/some/file.go:33"}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("nil sugar", func(t *testing.T) {
+		l := gormzap.NewFromSugar(nil)
+
+		l.Print("log", "/some/file.go:33", "foo")
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		l, err := gormzap.NewWithOptions(zap.NewNop(), gormzap.WithSlowThreshold(time.Second))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if l == nil {
+			t.Fatalf("Expected logger but got nil")
+		}
+	})
+
+	testCases := []struct {
+		name     string
+		origin   *zap.Logger
+		opts     []gormzap.LoggerOption
+		expected string
+	}{
+		{
+			name:     "nil origin",
+			expected: "gormzap: origin logger is nil",
+		},
+		{
+			name:     "nil encoder",
+			origin:   zap.NewNop(),
+			opts:     []gormzap.LoggerOption{gormzap.WithRecordToFields(nil)},
+			expected: "gormzap: RecordToFields func is nil",
+		},
+		{
+			name:     "negative slow threshold",
+			origin:   zap.NewNop(),
+			opts:     []gormzap.LoggerOption{gormzap.WithSlowThreshold(-time.Second)},
+			expected: "gormzap: negative slow threshold -1s",
+		},
+		{
+			name:     "explain without slow threshold",
+			origin:   zap.NewNop(),
+			opts:     []gormzap.LoggerOption{gormzap.WithExplain(&sql.DB{}, "postgres")},
+			expected: "gormzap: explain requires slow threshold to be set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := gormzap.NewWithOptions(tc.origin, tc.opts...)
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, err)
+			}
+			if l != nil {
+				t.Fatalf("Expected nil logger but got %v", l)
+			}
+		})
+	}
+}
+
+func TestLogger_getters(t *testing.T) {
+	l := gormzap.New(
+		zap.NewNop(),
+		gormzap.WithLevel(zap.InfoLevel),
+		gormzap.WithSlowThreshold(time.Second),
+		gormzap.WithRecordToFields(gormzap.ParameterizedRecordToFields),
+	)
+
+	if l.Level() != zap.InfoLevel {
+		t.Fatalf("Expected level %s but got %s", zap.InfoLevel, l.Level())
+	}
+	if l.SlowThreshold() != time.Second {
+		t.Fatalf("Expected slow threshold %s but got %s", time.Second, l.SlowThreshold())
+	}
+	if reflect.ValueOf(l.Encoder()).Pointer() != reflect.ValueOf(gormzap.ParameterizedRecordToFields).Pointer() {
+		t.Fatalf("Expected ParameterizedRecordToFields encoder")
+	}
+}
+
+func TestLogger_CloneWith(t *testing.T) {
+	l, buf := logger(gormzap.WithRules(gormzap.SelectStarRule(zap.InfoLevel)))
+	c := l.CloneWith(
+		gormzap.WithLevel(zap.WarnLevel),
+		gormzap.WithRules(gormzap.UnboundedWriteRule(zap.ErrorLevel)),
+	)
+
+	for _, gl := range []*gormzap.Logger{l, c} {
+		gl.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"DELETE FROM test",
+			[]interface{}{},
+			int64(1),
+		)
+	}
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"DELETE FROM test","sql.rows_affected":1}`,
+		`{"level":"error","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"DELETE FROM test","sql.rows_affected":1,"sql.lint":"unbounded_write"}`,
+	}
+	for i, e := range expected {
+		if actual := buf.Lines()[i]; actual != e {
+			t.Fatalf("Expected %s but got %s", e, actual)
+		}
+	}
+}
+
+func TestNewWithCore(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), buf, zapcore.InfoLevel)
+
+	l := gormzap.NewWithCore(core, gormzap.WithLevel(zap.InfoLevel))
+
+	l.Print("log", "/some/file.go:33", "foo")
+	expected := `{"msg":"foo","sql.source":"/some/file.go:33"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestNewFromSugar(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), buf, zapcore.DebugLevel)
+	sugar := zap.New(core).Sugar().With("app", "test")
+
+	l := gormzap.NewFromSugar(sugar)
+
+	l.Print("log", "/some/file.go:33", "foo")
+	expected := `{"msg":"foo","app":"test","sql.source":"/some/file.go:33"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestNewNop(t *testing.T) {
+	l := gormzap.NewNop()
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE id = $1",
+		[]interface{}{42},
+		int64(1),
+	)
+}
+
+func logger(opts ...gormzap.LoggerOption) (*gormzap.Logger, *zaptest.Buffer) {
+	z, buf := zapLogger()
+	return gormzap.New(z, opts...), buf
 }
 
-func logger() (*gormzap.Logger, *zaptest.Buffer) {
+func zapLogger() (*zap.Logger, *zaptest.Buffer) {
 	buf := &zaptest.Buffer{}
+	return zapLoggerTo(buf), buf
+}
 
+// zapLoggerTo returns the zap logger of zapLogger writing to ws.
+func zapLoggerTo(ws zapcore.WriteSyncer) *zap.Logger {
 	encoderCfg := zapcore.EncoderConfig{
 		MessageKey:     "msg",
 		LevelKey:       "level",
@@ -147,8 +924,141 @@ func logger() (*gormzap.Logger, *zaptest.Buffer) {
 		EncodeTime:     zapcore.ISO8601TimeEncoder,
 		EncodeDuration: zapcore.StringDurationEncoder,
 	}
-	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), buf, zapcore.DebugLevel)
-	z := zap.New(core)
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), ws, zapcore.DebugLevel)
+
+	return zap.New(core)
+}
+
+// observer returns a logger discarding encoded logs but keeping every record
+// in the returned recordSink, as gormzaptest.New does. gormzaptest is a
+// separate module, which gormzap tests do not depend on.
+func observer(opts ...gormzap.LoggerOption) (*gormzap.Logger, *recordSink) {
+	records := &recordSink{}
+	opts = append(opts[:len(opts):len(opts)], gormzap.WithSinks(records))
+	return gormzap.New(zap.NewNop(), opts...), records
+}
+
+// recordSink is a concurrency-safe collection of written records.
+type recordSink struct {
+	mu      sync.Mutex
+	records []gormzap.Record
+}
+
+func (s *recordSink) WriteRecord(r gormzap.Record) {
+	s.mu.Lock()
+	s.records = append(s.records, r)
+	s.mu.Unlock()
+}
+
+func (s *recordSink) Len() int {
+	return len(s.AllRecords())
+}
+
+func (s *recordSink) AllRecords() []gormzap.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]gormzap.Record(nil), s.records...)
+}
+
+func (s *recordSink) TakeAll() []gormzap.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := s.records
+	s.records = nil
+	return ret
+}
+
+func (s *recordSink) FilterMessage(msg string) *recordSink {
+	filtered := &recordSink{}
+	for _, r := range s.AllRecords() {
+		if r.Message == msg {
+			filtered.records = append(filtered.records, r)
+		}
+	}
+	return filtered
+}
+
+func TestWithDurationMillis(t *testing.T) {
+	l, buf := logger(gormzap.WithDurationMillis(), gormzap.WithFieldPrefix("db."))
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*1500, "SELECT 1", []interface{}{}, int64(1))
+	expected := `{"level":"debug","msg":"gorm query","db.source":"/some/file.go:34","db.duration_ms":1500,"db.query":"SELECT 1","db.rows_affected":1}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestWithLevelMapping(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithLevel(zapcore.DebugLevel),
+		gormzap.WithLevelMapping(map[gormzap.GormLevel]zapcore.Level{
+			gormzap.GormInfo:  zapcore.InfoLevel,
+			gormzap.GormError: zapcore.WarnLevel,
+		}),
+	)
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("log", "/some/file.go:35", "some message")
+	l.Print("/some/file.go:36", errors.New("some serious error!"))
+	l.Print("log", "/some/file.go:37", errors.New("another error"))
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1}`,
+		`{"level":"info","msg":"some message","sql.source":"/some/file.go:35"}`,
+		`{"level":"warn","msg":"some serious error!","sql.source":"/some/file.go:36"}`,
+		`{"level":"warn","msg":"another error","sql.source":"/some/file.go:37"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
+
+func TestWithLevel_levelEnabler(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	l, buf := logger(gormzap.WithLevel(level))
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+	level.SetLevel(zapcore.DebugLevel)
+	l.Print("sql", "/some/file.go:36", time.Millisecond*5, "SELECT 2", []interface{}{}, int64(1))
 
-	return gormzap.New(z), buf
+	expected := []string{
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:35"}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:36","sql.duration":"5ms","sql.query":"SELECT 2","sql.rows_affected":1}`,
+	}
+	lines := buf.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines but got %d", len(expected), len(lines))
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
+
+func TestWithZapOptions(t *testing.T) {
+	var hooked int
+	l, buf := logger(gormzap.WithZapOptions(
+		zap.Fields(zap.String("app", "test")),
+		zap.Hooks(func(zapcore.Entry) error {
+			hooked++
+			return nil
+		}),
+	))
+
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+
+	expected := `{"level":"error","msg":"some serious error!","app":"test","sql.source":"/some/file.go:35"}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+	if hooked != 1 {
+		t.Fatalf("Expected hook to be called once but got %d", hooked)
+	}
 }
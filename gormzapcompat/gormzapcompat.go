@@ -0,0 +1,79 @@
+// Package gormzapcompat mirrors constructors of other zap loggers for gorm,
+// e.g. moul.io/zapgorm and zapgorm2, so that migrating to gormzap is mostly a
+// change of the import.
+//
+// Example usage, in place of zapgorm:
+//  db.SetLogger(gormzapcompat.New(z))
+//
+// zapgorm2 log modes map onto gorm v1, which has no log levels of its own:
+//  db.SetLogger(gormzapcompat.LogMode(gormzapcompat.New(z), gormzapcompat.Info))
+//
+// Record not found errors, which zapgorm2 can be configured to ignore, are
+// never logged by gorm v1.
+package gormzapcompat
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// DefaultSlowThreshold is the slow query threshold of New, the default of
+// zapgorm2.
+const DefaultSlowThreshold = 100 * time.Millisecond
+
+// LogLevel is a log mode of zapgorm2, with the same values as gorm v2 log
+// levels.
+type LogLevel int
+
+const (
+	// Silent disables all records.
+	Silent LogLevel = iota + 1
+	// Error enables only error records.
+	Error
+	// Warn enables error and slow query records.
+	Warn
+	// Info enables all records, logging queries with debug level.
+	Info
+)
+
+// New returns gormzap logger writing to z, configured with the defaults of
+// zapgorm2: Warn log mode, DefaultSlowThreshold, and the source of records
+// logged as the zap entry caller.
+func New(z *zap.Logger) *gormzap.Logger {
+	return gormzap.New(z,
+		WithLogMode(Warn),
+		gormzap.WithSlowThreshold(DefaultSlowThreshold),
+		gormzap.WithSourceAsCaller(),
+	)
+}
+
+// LogMode returns a copy of l with the given log mode, like LogMode of
+// zapgorm2.
+func LogMode(l *gormzap.Logger, level LogLevel) *gormzap.Logger {
+	return l.CloneWith(WithLogMode(level))
+}
+
+// WithLogMode returns gormzap Logger option that sets the given log mode,
+// with gormzap.WithLevel. Unknown modes are treated as Info.
+func WithLogMode(level LogLevel) gormzap.LoggerOption {
+	switch level {
+	case Silent:
+		return gormzap.WithLevel(zap.LevelEnablerFunc(func(zapcore.Level) bool {
+			return false
+		}))
+	case Error:
+		return gormzap.WithLevel(zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= zapcore.ErrorLevel
+		}))
+	case Warn:
+		return gormzap.WithLevel(zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= zapcore.WarnLevel
+		}))
+	default:
+		return gormzap.WithLevel(zapcore.DebugLevel)
+	}
+}
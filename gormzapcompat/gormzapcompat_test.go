@@ -0,0 +1,88 @@
+package gormzapcompat_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hypnoglow/gormzap/gormzapcompat"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name     string
+		level    gormzapcompat.LogLevel
+		expected []string
+	}{
+		{
+			name: "default",
+			expected: []string{
+				`{"level":"warn","caller":"/some/file.go:35","msg":"gorm query","sql.duration":"150ms","sql.query":"SELECT 2","sql.rows_affected":1,"sql.slow":true}`,
+				`{"level":"error","caller":"/some/file.go:36","msg":"some serious error!"}`,
+			},
+		},
+		{
+			name:  "silent",
+			level: gormzapcompat.Silent,
+		},
+		{
+			name:  "error",
+			level: gormzapcompat.Error,
+			expected: []string{
+				`{"level":"error","caller":"/some/file.go:36","msg":"some serious error!"}`,
+			},
+		},
+		{
+			name:  "info",
+			level: gormzapcompat.Info,
+			expected: []string{
+				`{"level":"debug","caller":"/some/file.go:34","msg":"gorm query","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1}`,
+				`{"level":"warn","caller":"/some/file.go:35","msg":"gorm query","sql.duration":"150ms","sql.query":"SELECT 2","sql.rows_affected":1,"sql.slow":true}`,
+				`{"level":"error","caller":"/some/file.go:36","msg":"some serious error!"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			z, buf := zapLogger()
+			l := gormzapcompat.New(z)
+			if tc.level != 0 {
+				l = gormzapcompat.LogMode(l, tc.level)
+			}
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+			l.Print("sql", "/some/file.go:35", time.Millisecond*150, "SELECT 2", []interface{}{}, int64(1))
+			l.Print("/some/file.go:36", errors.New("some serious error!"))
+
+			lines := buf.Lines()
+			if len(lines) != len(tc.expected) {
+				t.Fatalf("Expected %d lines but got %d: %v", len(tc.expected), len(lines), lines)
+			}
+			for i, e := range tc.expected {
+				if lines[i] != e {
+					t.Fatalf("Expected %s but got %s", e, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func zapLogger() (*zap.Logger, *zaptest.Buffer) {
+	buf := &zaptest.Buffer{}
+
+	encoderCfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		CallerKey:      "caller",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), buf, zapcore.DebugLevel)
+
+	return zap.New(core), buf
+}
@@ -0,0 +1,182 @@
+// Package gormzapdebug provides an HTTP handler with recent and slowest
+// queries logged with gormzap, so that operators can inspect live database
+// activity without grepping logs.
+//
+// The handler exposes logged queries, including bind values unless they are
+// disabled with gormzap.WithoutValues, so it must be served only on a
+// protected debug port.
+//
+// Example usage:
+//  rec := gormzapdebug.NewRecorder(100, 10)
+//  log := gormzap.New(z, gormzap.WithSinks(rec))
+//  http.Handle("/debug/queries", rec)
+package gormzapdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// Query is a recorded query.
+type Query struct {
+	Time         time.Time
+	Source       string
+	SQL          string
+	Duration     time.Duration
+	RowsAffected int64
+	Level        string
+}
+
+// Recorder is gormzap.RecordSink that keeps a ring buffer of recent queries
+// and the slowest queries seen, and serves them over HTTP. It is safe for
+// concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	recent  []Query
+	next    int
+	full    bool
+	slowest []Query
+	topN    int
+}
+
+// NewRecorder returns a new Recorder keeping size recent queries and topN
+// slowest queries. A non-positive size disables recent queries.
+func NewRecorder(size, topN int) *Recorder {
+	if size < 0 {
+		size = 0
+	}
+	return &Recorder{
+		recent: make([]Query, size),
+		topN:   topN,
+	}
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (r *Recorder) WriteRecord(rec gormzap.Record) {
+	if rec.SQL == "" {
+		return
+	}
+	q := Query{
+		Time:         time.Now(),
+		Source:       rec.Source,
+		SQL:          rec.SQL,
+		Duration:     rec.Duration,
+		RowsAffected: rec.RowsAffected,
+		Level:        rec.Level.String(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.recent) > 0 {
+		r.recent[r.next] = q
+		r.next = (r.next + 1) % len(r.recent)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+
+	if r.topN > 0 && (len(r.slowest) < r.topN || q.Duration > r.slowest[len(r.slowest)-1].Duration) {
+		i := sort.Search(len(r.slowest), func(i int) bool {
+			return r.slowest[i].Duration < q.Duration
+		})
+		if len(r.slowest) < r.topN {
+			r.slowest = append(r.slowest, Query{})
+		}
+		copy(r.slowest[i+1:], r.slowest[i:])
+		r.slowest[i] = q
+	}
+}
+
+// Recent returns recent queries, newest first.
+func (r *Recorder) Recent() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.recent)
+	}
+	queries := make([]Query, 0, n)
+	for i := 1; i <= n; i++ {
+		queries = append(queries, r.recent[(r.next-i+len(r.recent))%len(r.recent)])
+	}
+	return queries
+}
+
+// Slowest returns the slowest queries, slowest first.
+func (r *Recorder) Slowest() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Query(nil), r.slowest...)
+}
+
+// ServeHTTP implements http.Handler. It renders recent and slowest queries as
+// HTML, or as JSON if requested with "format=json" query parameter or
+// "Accept: application/json" header.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data := struct {
+		Recent  []jsonQuery `json:"recent"`
+		Slowest []jsonQuery `json:"slowest"`
+	}{
+		Recent:  toJSON(r.Recent()),
+		Slowest: toJSON(r.Slowest()),
+	}
+
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTemplate.Execute(w, data)
+}
+
+type jsonQuery struct {
+	Time         time.Time `json:"time"`
+	Source       string    `json:"source"`
+	SQL          string    `json:"sql"`
+	DurationMS   float64   `json:"duration_ms"`
+	RowsAffected int64     `json:"rows_affected"`
+	Level        string    `json:"level"`
+}
+
+func toJSON(queries []Query) []jsonQuery {
+	res := make([]jsonQuery, len(queries))
+	for i, q := range queries {
+		res[i] = jsonQuery{
+			Time:         q.Time,
+			Source:       q.Source,
+			SQL:          q.SQL,
+			DurationMS:   float64(q.Duration) / float64(time.Millisecond),
+			RowsAffected: q.RowsAffected,
+			Level:        q.Level,
+		}
+	}
+	return res
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><title>gormzap queries</title></head>
+<body>
+{{define "table"}}<table border="1" cellpadding="4">
+<tr><th>Time</th><th>Duration, ms</th><th>Rows</th><th>Level</th><th>Source</th><th>SQL</th></tr>
+{{range .}}<tr><td>{{.Time.Format "15:04:05.000"}}</td><td>{{.DurationMS}}</td><td>{{.RowsAffected}}</td><td>{{.Level}}</td><td>{{.Source}}</td><td><code>{{.SQL}}</code></td></tr>
+{{end}}</table>{{end}}
+<h2>Slowest queries</h2>
+{{template "table" .Slowest}}
+<h2>Recent queries</h2>
+{{template "table" .Recent}}
+</body>
+</html>
+`))
@@ -0,0 +1,82 @@
+package gormzapdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapdebug"
+	"go.uber.org/zap"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := gormzapdebug.NewRecorder(2, 2)
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(rec))
+
+	for i, d := range []time.Duration{30, 10, 50, 20} {
+		l.Print("sql", "/some/file.go:34", d*time.Millisecond, "SELECT "+string(rune('1'+i)), []interface{}{}, int64(1))
+	}
+
+	sqls := func(queries []gormzapdebug.Query) string {
+		var s []string
+		for _, q := range queries {
+			s = append(s, q.SQL)
+		}
+		return strings.Join(s, ",")
+	}
+
+	if actual := sqls(rec.Recent()); actual != "SELECT 4,SELECT 3" {
+		t.Fatalf("Unexpected recent queries: %s", actual)
+	}
+	if actual := sqls(rec.Slowest()); actual != "SELECT 3,SELECT 1" {
+		t.Fatalf("Unexpected slowest queries: %s", actual)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+
+		var data struct {
+			Slowest []struct {
+				SQL        string  `json:"sql"`
+				DurationMS float64 `json:"duration_ms"`
+			} `json:"slowest"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(data.Slowest) != 2 || data.Slowest[0].SQL != "SELECT 3" || data.Slowest[0].DurationMS != 50 {
+			t.Fatalf("Unexpected slowest queries: %+v", data.Slowest)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Fatalf("Unexpected content type: %s", ct)
+		}
+		if body := w.Body.String(); !strings.Contains(body, "<code>SELECT 4</code>") {
+			t.Fatalf("Expected body to contain recent query, got: %s", body)
+		}
+	})
+}
+
+func TestNewRecorder_negativeSize(t *testing.T) {
+	rec := gormzapdebug.NewRecorder(-1, 1)
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(rec))
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond, "SELECT 1", []interface{}{}, int64(1))
+
+	if n := len(rec.Recent()); n != 0 {
+		t.Fatalf("Expected no recent queries but got %d", n)
+	}
+	if n := len(rec.Slowest()); n != 1 {
+		t.Fatalf("Expected 1 slowest query but got %d", n)
+	}
+}
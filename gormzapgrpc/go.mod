@@ -0,0 +1,12 @@
+module github.com/hypnoglow/gormzap/gormzapgrpc
+
+go 1.16
+
+require (
+	github.com/hypnoglow/gormzap v0.0.0-20261014181209-c71e9d6a1274
+	go.uber.org/zap v1.8.0
+	google.golang.org/grpc v1.33.2
+)
+
+// Local development against the gormzap module in the parent directory.
+replace github.com/hypnoglow/gormzap => ../
@@ -0,0 +1,234 @@
+// Package gormzapgrpc streams gormzap records over gRPC to a collector
+// service, so that query telemetry can bypass the log pipeline. The service
+// is defined in collector.proto, and records are encoded as Record messages
+// of gormzapproto package. A collector can be implemented in any language,
+// or in Go with RegisterCollector.
+//
+// Example usage:
+//  conn, err := grpc.Dial("collector:4317", grpc.WithInsecure())
+//  if err != nil {
+//      panic(err)
+//  }
+//  sink := gormzapgrpc.NewSink(conn)
+//  defer sink.Close()
+//  log := gormzap.New(z, gormzap.WithSinks(sink))
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// gRPC.
+package gormzapgrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapproto"
+	"google.golang.org/grpc"
+)
+
+// DefaultBufferSize is the default number of records buffered by Sink.
+const DefaultBufferSize = 1024
+
+const exportMethod = "/gormzap.Collector/Export"
+
+var exportStream = &grpc.StreamDesc{
+	StreamName:    "Export",
+	ClientStreams: true,
+}
+
+// Sink is gormzap.RecordSink that streams records to the collector service.
+// Records are buffered and sent from a separate goroutine, so that queries
+// are never blocked by the collector. Records are dropped when the buffer is
+// full or the collector is unavailable, see Dropped.
+type Sink struct {
+	conn grpc.ClientConnInterface
+
+	mu      sync.RWMutex
+	closed  bool
+	records chan []byte
+	done    chan struct{}
+
+	dropped int64
+	err     error
+}
+
+// Option configures Sink.
+type Option func(*Sink)
+
+// WithBufferSize returns Sink option that sets the number of records
+// buffered by the sink. By default, DefaultBufferSize is used. A non-positive
+// size disables buffering, so that records are dropped while one is sent.
+func WithBufferSize(n int) Option {
+	return func(s *Sink) {
+		if n < 0 {
+			n = 0
+		}
+		s.records = make(chan []byte, n)
+	}
+}
+
+// NewSink returns a new Sink streaming records over conn. The sink must be
+// closed with Close to send buffered records.
+func NewSink(conn grpc.ClientConnInterface, opts ...Option) *Sink {
+	s := &Sink{
+		conn:    conn,
+		records: make(chan []byte, DefaultBufferSize),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.run()
+	return s
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (s *Sink) WriteRecord(r gormzap.Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		atomic.AddInt64(&s.dropped, 1)
+		return
+	}
+
+	select {
+	case s.records <- gormzapproto.Marshal(r):
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of records dropped so far.
+func (s *Sink) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// Close sends buffered records and closes the stream. It returns the first
+// error of the stream, if any. Records written after Close are dropped.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.records)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+	return s.err
+}
+
+// run sends records until the sink is closed. If the stream fails, the
+// record is dropped and a new stream is opened for the next one.
+func (s *Sink) run() {
+	defer close(s.done)
+
+	var stream grpc.ClientStream
+	for msg := range s.records {
+		if stream == nil {
+			var err error
+			stream, err = s.conn.NewStream(context.Background(), exportStream, exportMethod)
+			if err != nil {
+				s.fail(err)
+				continue
+			}
+		}
+		if err := stream.SendMsg(&message{b: msg}); err != nil {
+			s.fail(err)
+			stream = nil
+		}
+	}
+
+	if stream != nil {
+		if err := stream.CloseSend(); err != nil {
+			s.fail(err)
+			return
+		}
+		if err := stream.RecvMsg(&message{}); err != nil {
+			s.setErr(err)
+		}
+	}
+}
+
+// fail records the stream error and drops the record.
+func (s *Sink) fail(err error) {
+	atomic.AddInt64(&s.dropped, 1)
+	s.setErr(err)
+}
+
+func (s *Sink) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// RegisterCollector registers the collector service on server, calling f for
+// each received record. f is called sequentially for the records of a
+// stream, and concurrently for different streams.
+func RegisterCollector(server *grpc.Server, f func(r gormzapproto.Record)) {
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "gormzap.Collector",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Export",
+			ClientStreams: true,
+			Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				return export(stream, f)
+			},
+		}},
+		Metadata: "gormzapgrpc/collector.proto",
+	}, nil)
+}
+
+func export(stream grpc.ServerStream, f func(r gormzapproto.Record)) error {
+	var received int64
+	for {
+		var msg message
+		err := stream.RecvMsg(&msg)
+		if errors.Is(err, io.EOF) {
+			return stream.SendMsg(&message{b: appendReceived(nil, received)})
+		}
+		if err != nil {
+			return err
+		}
+
+		rec, err := gormzapproto.Unmarshal(msg.b)
+		if err != nil {
+			return err
+		}
+		f(rec)
+		received++
+	}
+}
+
+// message is an encoded protobuf message. It implements Marshal and
+// Unmarshal methods, which the default gRPC codec uses as is.
+type message struct {
+	b []byte
+}
+
+func (m *message) Reset()                   { m.b = nil }
+func (m *message) String() string           { return string(m.b) }
+func (m *message) ProtoMessage()            {}
+func (m *message) Marshal() ([]byte, error) { return m.b, nil }
+
+func (m *message) Unmarshal(b []byte) error {
+	m.b = append(m.b[:0], b...)
+	return nil
+}
+
+// appendReceived appends ExportResponse message to dst.
+func appendReceived(dst []byte, received int64) []byte {
+	if received == 0 {
+		return dst
+	}
+	dst = append(dst, 1<<3) // Field 1, varint.
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(received))
+	return append(dst, buf[:n]...)
+}
@@ -0,0 +1,76 @@
+package gormzapgrpc_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapgrpc"
+	"github.com/hypnoglow/gormzap/gormzapproto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSink(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+
+	var mu sync.Mutex
+	var records []gormzapproto.Record
+
+	server := grpc.NewServer()
+	gormzapgrpc.RegisterCollector(server, func(r gormzapproto.Record) {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, r)
+	})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	sink := gormzapgrpc.NewSink(conn)
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(sink))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:35", time.Millisecond*5, "DELETE FROM users", []interface{}{}, int64(1))
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := sink.Dropped(); n != 0 {
+		t.Fatalf("Expected no dropped records but got %d", n)
+	}
+
+	sink.WriteRecord(gormzap.Record{Message: "late"})
+	if n := sink.Dropped(); n != 1 {
+		t.Fatalf("Expected 1 dropped record but got %d", n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records but got %d", len(records))
+	}
+	if records[0].Table != "users" || records[1].Operation != "DELETE" {
+		t.Fatalf("Unexpected records: %+v", records)
+	}
+}
+
+func TestWithBufferSize_negative(t *testing.T) {
+	sink := gormzapgrpc.NewSink(nil, gormzapgrpc.WithBufferSize(-1))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
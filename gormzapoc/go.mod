@@ -0,0 +1,12 @@
+module github.com/hypnoglow/gormzap/gormzapoc
+
+go 1.16
+
+require (
+	github.com/hypnoglow/gormzap v0.0.0-20261014181226-2626cced1e73
+	go.opencensus.io v0.24.0
+	go.uber.org/zap v1.8.0
+)
+
+// Local development against the gormzap module in the parent directory.
+replace github.com/hypnoglow/gormzap => ../
@@ -0,0 +1,150 @@
+// Package gormzapoc records OpenCensus stats of queries logged with gormzap:
+// query count, error count and latency distribution.
+//
+// Example usage:
+//  if err := view.Register(gormzapoc.DefaultViews...); err != nil {
+//      panic(err)
+//  }
+//  log := gormzap.New(z, gormzap.WithSinks(gormzapoc.NewSink()))
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// OpenCensus.
+package gormzapoc
+
+import (
+	"context"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap/zapcore"
+)
+
+// Measures recorded by Sink.
+var (
+	MeasureQueries = stats.Int64("gormzap/queries", "Number of SQL queries", stats.UnitDimensionless)
+	MeasureErrors  = stats.Int64("gormzap/errors", "Number of logged errors", stats.UnitDimensionless)
+	MeasureLatency = stats.Float64("gormzap/latency", "SQL query latency", stats.UnitMilliseconds)
+)
+
+// Tag keys of query count and latency.
+var (
+	// KeyTable is the tag key of the table name, see WithTableTag.
+	KeyTable = tag.MustNewKey("gormzap_table")
+	// KeyOperation is the tag key of the statement operation, see
+	// WithOperationTag.
+	KeyOperation = tag.MustNewKey("gormzap_operation")
+)
+
+// DefaultLatencyBounds are the bucket boundaries of LatencyView, in
+// milliseconds.
+var DefaultLatencyBounds = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// DefaultLatencyDistribution is the latency distribution of LatencyView, and
+// of NewLatencyView without bounds.
+var DefaultLatencyDistribution = view.Distribution(DefaultLatencyBounds...)
+
+// Views of the measures recorded by Sink.
+var (
+	QueryCountView = &view.View{
+		Name:        "gormzap/queries",
+		Description: "Number of SQL queries",
+		Measure:     MeasureQueries,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{KeyTable, KeyOperation},
+	}
+	ErrorCountView = &view.View{
+		Name:        "gormzap/errors",
+		Description: "Number of logged errors",
+		Measure:     MeasureErrors,
+		Aggregation: view.Count(),
+	}
+	LatencyView = NewLatencyView()
+)
+
+// NewLatencyView returns a view of query latency distribution with the given
+// bucket boundaries in milliseconds, to be registered instead of LatencyView
+// when default buckets do not match the database latency profile, e.g.
+//  view.Register(gormzapoc.QueryCountView, gormzapoc.NewLatencyView(0.1, 0.5, 1, 5))
+//
+// Without bounds, DefaultLatencyDistribution is used.
+func NewLatencyView(bounds ...float64) *view.View {
+	aggregation := DefaultLatencyDistribution
+	if len(bounds) > 0 {
+		aggregation = view.Distribution(bounds...)
+	}
+	return &view.View{
+		Name:        "gormzap/latency",
+		Description: "Distribution of SQL query latency",
+		Measure:     MeasureLatency,
+		Aggregation: aggregation,
+		TagKeys:     []tag.Key{KeyTable, KeyOperation},
+	}
+}
+
+// DefaultViews are the views of the measures recorded by Sink, which should
+// be registered with view.Register.
+var DefaultViews = []*view.View{QueryCountView, ErrorCountView, LatencyView}
+
+// Sink is gormzap.RecordSink that records OpenCensus stats of the records.
+type Sink struct {
+	tables     *gormzap.TableLabeler
+	operations bool
+}
+
+// Option configures Sink.
+type Option func(*Sink)
+
+// WithTableTag returns Sink option that tags query count and latency with
+// the table name as KeyTable. At most max distinct tables are tagged by name,
+// and the rest are tagged with gormzap.OtherTable.
+func WithTableTag(max int) Option {
+	return func(s *Sink) {
+		s.tables = gormzap.NewTableLabeler(max)
+	}
+}
+
+// WithOperationTag returns Sink option that tags query count and latency
+// with the statement operation as KeyOperation: one of select, insert,
+// update, delete, ddl or other, as returned by gormzap.OperationLabel.
+func WithOperationTag() Option {
+	return func(s *Sink) {
+		s.operations = true
+	}
+}
+
+// NewSink returns a new Sink.
+func NewSink(opts ...Option) *Sink {
+	s := &Sink{}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (s *Sink) WriteRecord(r gormzap.Record) {
+	ctx := context.Background()
+
+	if r.SQL != "" {
+		var mutators []tag.Mutator
+		if s.tables != nil {
+			if table := s.tables.Label(r.Table()); table != "" {
+				mutators = append(mutators, tag.Upsert(KeyTable, table))
+			}
+		}
+		if s.operations {
+			mutators = append(mutators, tag.Upsert(KeyOperation, gormzap.OperationLabel(r)))
+		}
+
+		_ = stats.RecordWithTags(ctx, mutators,
+			MeasureQueries.M(1),
+			MeasureLatency.M(float64(r.Duration)/float64(time.Millisecond)),
+		)
+	}
+	if r.Level >= zapcore.ErrorLevel {
+		stats.Record(ctx, MeasureErrors.M(1))
+	}
+}
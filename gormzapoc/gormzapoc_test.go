@@ -0,0 +1,143 @@
+package gormzapoc_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapoc"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/zap"
+)
+
+func TestSink(t *testing.T) {
+	if err := view.Register(gormzapoc.DefaultViews...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer view.Unregister(gormzapoc.DefaultViews...)
+
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(gormzapoc.NewSink()))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*15, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+
+	testCases := []struct {
+		view     *view.View
+		expected int64
+	}{
+		{view: gormzapoc.QueryCountView, expected: 2},
+		{view: gormzapoc.ErrorCountView, expected: 1},
+		{view: gormzapoc.LatencyView, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.view.Name, func(t *testing.T) {
+			rows, err := view.RetrieveData(tc.view.Name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("Expected 1 row but got %d", len(rows))
+			}
+
+			var actual int64
+			switch data := rows[0].Data.(type) {
+			case *view.CountData:
+				actual = data.Value
+			case *view.DistributionData:
+				actual = data.Count
+			}
+			if actual != tc.expected {
+				t.Fatalf("Expected %d but got %d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithTableTag(t *testing.T) {
+	if err := view.Register(gormzapoc.QueryCountView); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer view.Unregister(gormzapoc.QueryCountView)
+
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(gormzapoc.NewSink(gormzapoc.WithTableTag(1))))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM orders", []interface{}{}, int64(1))
+
+	rows, err := view.RetrieveData(gormzapoc.QueryCountView.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	actual := make(map[string]int64)
+	for _, row := range rows {
+		actual[row.Tags[0].Value] = row.Data.(*view.CountData).Value
+	}
+	expected := map[string]int64{"users": 2, gormzap.OtherTable: 1}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWithOperationTag(t *testing.T) {
+	if err := view.Register(gormzapoc.QueryCountView); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer view.Unregister(gormzapoc.QueryCountView)
+
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(gormzapoc.NewSink(gormzapoc.WithOperationTag())))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "UPDATE users SET name = 'foo'", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "UPDATE users SET name = 'bar'", []interface{}{}, int64(1))
+
+	rows, err := view.RetrieveData(gormzapoc.QueryCountView.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	actual := make(map[string]int64)
+	for _, row := range rows {
+		actual[row.Tags[0].Value] = row.Data.(*view.CountData).Value
+	}
+	expected := map[string]int64{gormzap.OperationSelect: 1, gormzap.OperationUpdate: 2}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestNewLatencyView(t *testing.T) {
+	v := gormzapoc.NewLatencyView(1, 10)
+	if err := view.Register(v); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer view.Unregister(v)
+
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(gormzapoc.NewSink()))
+	l.Print("sql", "/some/file.go:34", time.Microsecond*500, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*50, "SELECT 1", []interface{}{}, int64(1))
+
+	rows, err := view.RetrieveData(v.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []int64{1, 1, 1}
+	actual := rows[0].Data.(*view.DistributionData).CountPerBucket
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestNewLatencyView_defaultBounds(t *testing.T) {
+	v := gormzapoc.NewLatencyView()
+	if v.Aggregation != gormzapoc.DefaultLatencyDistribution {
+		t.Fatalf("Expected default latency distribution")
+	}
+	if !reflect.DeepEqual(v.Aggregation.Buckets, gormzapoc.DefaultLatencyBounds) {
+		t.Fatalf("Expected buckets %v but got %v", gormzapoc.DefaultLatencyBounds, v.Aggregation.Buckets)
+	}
+}
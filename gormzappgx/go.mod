@@ -0,0 +1,28 @@
+module github.com/hypnoglow/gormzap/gormzappgx
+
+go 1.19
+
+require (
+	github.com/hypnoglow/gormzap v0.0.0-20261014181348-8c9642c5caf3
+	github.com/hypnoglow/gormzap/gormzaptest v0.0.0-20261014181348-8c9642c5caf3
+	github.com/jackc/pgx/v5 v5.5.5
+	go.uber.org/zap v1.8.0
+)
+
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jinzhu/gorm v1.9.16 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	go.uber.org/atomic v1.3.2 // indirect
+	go.uber.org/multierr v1.1.0 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+// Local development against the gormzap modules in the parent directory.
+replace (
+	github.com/hypnoglow/gormzap => ../
+	github.com/hypnoglow/gormzap/gormzaptest => ../gormzaptest
+)
@@ -0,0 +1,90 @@
+// Package gormzappgx adapts gormzap logger to pgx tracelog, so that queries
+// made with raw pgx are logged with the same records as those made with gorm.
+//
+// Example usage:
+//  config.ConnConfig.Tracer = &tracelog.TraceLog{
+//  	Logger:   gormzappgx.NewLogger(log),
+//  	LogLevel: tracelog.LogLevelInfo,
+//  }
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// pgx and the Go version it requires.
+package gormzappgx
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/tracelog"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// Logger implements tracelog.Logger, converting pgx query events into
+// gormzap records. Query and BatchQuery events are logged as SQL query
+// records, preceded by an error record carrying the failing statement if the
+// query failed, as gorm does. Other events are only logged if they carry an
+// error. Batch queries are logged with zero duration, because pgx only times
+// the whole batch.
+//
+// Durations are read from "time" key, as tracelog logs them. Both records are
+// bound to the query context, see gormzap.Logger.WithContext.
+type Logger struct {
+	logger *gormzap.Logger
+}
+
+// NewLogger returns new Logger logging pgx events with l.
+func NewLogger(l *gormzap.Logger) *Logger {
+	return &Logger{logger: l}
+}
+
+var _ tracelog.Logger = (*Logger)(nil)
+
+// Log implements tracelog.Logger.
+func (l *Logger) Log(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]interface{}) {
+	src := source(l.logger)
+	err, _ := data["err"].(error)
+
+	sql, ok := data["sql"].(string)
+	if !ok || (msg != "Query" && msg != "BatchQuery") {
+		if err != nil {
+			l.logger.WithContext(ctx).Print(src, err)
+		}
+		return
+	}
+
+	args, _ := data["args"].([]interface{})
+	duration, _ := data["time"].(time.Duration)
+	var rowsAffected int64
+	if tag, ok := data["commandTag"].(string); ok {
+		rowsAffected = pgconn.NewCommandTag(tag).RowsAffected()
+	}
+	l.logger.LogQueryFrom(ctx, src, sql, args, duration, rowsAffected, err)
+}
+
+// source returns file and line of the first caller outside of pgx and this
+// package, like gorm does for its queries, or empty string if l does not log
+// sources.
+func source(l *gormzap.Logger) string {
+	if !l.SourceEnabled() {
+		return ""
+	}
+
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/jackc/pgx/") &&
+			!strings.HasPrefix(f.Function, "github.com/hypnoglow/gormzap/gormzappgx.") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
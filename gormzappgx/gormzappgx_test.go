@@ -0,0 +1,98 @@
+package gormzappgx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/tracelog"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzappgx"
+	"github.com/hypnoglow/gormzap/gormzaptest"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		l, records := gormzaptest.New()
+		gormzappgx.NewLogger(l).Log(context.Background(), tracelog.LogLevelInfo, "Query", map[string]interface{}{
+			"sql":        "UPDATE users SET name = $1 WHERE id = $2",
+			"args":       []interface{}{"Jane", 1},
+			"time":       time.Millisecond,
+			"commandTag": "UPDATE 1",
+		})
+
+		recs := records.AllRecords()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		rec := recs[0]
+		if rec.SQL != "UPDATE users SET name = 'Jane' WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", rec.SQL)
+		}
+		if rec.Duration != time.Millisecond {
+			t.Errorf("unexpected duration: %v", rec.Duration)
+		}
+		if rec.RowsAffected != 1 {
+			t.Errorf("expected 1 row affected, got %d", rec.RowsAffected)
+		}
+		if rec.Source == "" {
+			t.Errorf("expected source to be set")
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		type traceKey struct{}
+		l, records := gormzaptest.New(gormzap.WithTraceIDs(func(ctx context.Context) (string, string) {
+			id, _ := ctx.Value(traceKey{}).(string)
+			return id, ""
+		}))
+		ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+		gormzappgx.NewLogger(l).Log(ctx, tracelog.LogLevelError, "Query", map[string]interface{}{
+			"sql":  "DELETE FROM users WHERE id = $1",
+			"args": []interface{}{1},
+			"err":  errors.New("permission denied"),
+			"time": time.Millisecond,
+		})
+
+		recs := records.AllRecords()
+		if len(recs) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(recs))
+		}
+		if recs[0].Level != zapcore.ErrorLevel || recs[0].Statement != "DELETE FROM users WHERE id = $1" {
+			t.Errorf("unexpected error record: %s %q", recs[0].Level, recs[0].Statement)
+		}
+		if recs[1].SQL != "DELETE FROM users WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", recs[1].SQL)
+		}
+		for i, rec := range recs {
+			if rec.TraceID != "trace-1" {
+				t.Errorf("record %d: expected trace ID from the query context, got %q", i, rec.TraceID)
+			}
+		}
+	})
+
+	t.Run("other events", func(t *testing.T) {
+		l, records := gormzaptest.New()
+		logger := gormzappgx.NewLogger(l)
+		logger.Log(context.Background(), tracelog.LogLevelInfo, "Prepare", map[string]interface{}{
+			"name": "stmt",
+			"sql":  "SELECT 1",
+			"time": time.Millisecond,
+		})
+		logger.Log(context.Background(), tracelog.LogLevelError, "Connect", map[string]interface{}{
+			"host": "localhost",
+			"err":  errors.New("connection refused"),
+		})
+
+		recs := records.AllRecords()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		if recs[0].Level != zapcore.ErrorLevel || recs[0].Message != "connection refused" {
+			t.Errorf("unexpected record: %s %q", recs[0].Level, recs[0].Message)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/hypnoglow/gormzap/gormzapplugin
+
+go 1.16
+
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/hypnoglow/gormzap v0.0.0-20261014181348-8c9642c5caf3
+	github.com/hypnoglow/gormzap/gormzaptest v0.0.0-20261014181348-8c9642c5caf3
+	github.com/jinzhu/gorm v1.9.16
+)
+
+// Local development against the gormzap modules in the parent directory.
+replace (
+	github.com/hypnoglow/gormzap => ../
+	github.com/hypnoglow/gormzap/gormzaptest => ../gormzaptest
+)
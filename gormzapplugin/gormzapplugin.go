@@ -0,0 +1,63 @@
+// Package gormzapplugin provides gorm callbacks which enrich gormzap records
+// with the model struct name, its table name, primary key value and gorm callback chain of
+// the operation, which gorm does not pass to its logger.
+//
+// Example usage:
+//  log := gormzap.New(z)
+//  orm.SetLogger(log)
+//  gormzapplugin.Register(orm, log)
+//
+// Error records of the operations also carry the statement of the failing
+// query, see gormzap.ModelLogger.WithStatementFunc.
+//
+// Note that records of the operations made by registered callbacks are
+// logged with the given logger, even if another logger is set for the DB.
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// gorm.
+package gormzapplugin
+
+import (
+	"fmt"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/jinzhu/gorm"
+)
+
+// Register registers callbacks of db, which set a logger adding model info
+// to records of each create, query, update, delete and row query operation.
+func Register(db *gorm.DB, l *gormzap.Logger) {
+	cb := db.Callback()
+	cb.Create().Before("gorm:begin_transaction").Register("gormzap:create", setLogger(l, "create"))
+	cb.Query().Before("gorm:query").Register("gormzap:query", setLogger(l, "query"))
+	cb.Update().Before("gorm:assign_updating_attributes").Register("gormzap:update", setLogger(l, "update"))
+	cb.Delete().Before("gorm:begin_transaction").Register("gormzap:delete", setLogger(l, "delete"))
+	cb.RowQuery().Before("gorm:row_query").Register("gormzap:row_query", setLogger(l, "row_query"))
+}
+
+// setLogger returns callback setting logger of the operation scope. gorm
+// clones DB for every operation, so the logger is used only for its queries.
+func setLogger(l *gormzap.Logger, callback string) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		ml := l.WithModelInfo(modelInfo(scope, callback)).WithStatementFunc(func() (string, []interface{}) {
+			return scope.SQL, scope.SQLVars
+		})
+		scope.DB().SetLogger(ml)
+	}
+}
+
+func modelInfo(scope *gorm.Scope, callback string) gormzap.ModelInfo {
+	info := gormzap.ModelInfo{Callback: callback}
+	if scope.Value == nil {
+		return info
+	}
+
+	if ms := scope.GetModelStruct(); ms.ModelType != nil {
+		info.Model = ms.ModelType.Name()
+		info.Schema = scope.TableName()
+	}
+	if !scope.PrimaryKeyZero() {
+		info.PrimaryKey = fmt.Sprint(scope.PrimaryKeyValue())
+	}
+	return info
+}
@@ -0,0 +1,91 @@
+package gormzapplugin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hypnoglow/gormzap/gormzapplugin"
+	"github.com/hypnoglow/gormzap/gormzaptest"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+func TestRegister(t *testing.T) {
+	m, err := gormzaptest.OpenMock("postgres")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Close()
+
+	gormzapplugin.Register(m.DB, m.Logger)
+
+	m.Mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "foo"))
+	m.Mock.ExpectBegin()
+	m.Mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.Mock.ExpectCommit()
+
+	var u User
+	m.DB.First(&u)
+	m.DB.Model(&u).Update("name", "bar")
+
+	if err := m.Mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		sql        string
+		primaryKey string
+		callback   string
+	}{
+		{sql: "SELECT", primaryKey: "", callback: "query"},
+		{sql: "UPDATE", primaryKey: "1", callback: "update"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.callback, func(t *testing.T) {
+			records := m.Records.FilterSQLContains(tc.sql).AllRecords()
+			if len(records) != 1 {
+				t.Fatalf("Expected 1 record but got %d", len(records))
+			}
+
+			rec := records[0]
+			if rec.Model != "User" || rec.Schema != "users" || rec.PrimaryKey != tc.primaryKey || rec.Callback != tc.callback {
+				t.Fatalf("Unexpected model info: %q, %q, %q, %q", rec.Model, rec.Schema, rec.PrimaryKey, rec.Callback)
+			}
+		})
+	}
+}
+
+func TestRegister_error(t *testing.T) {
+	m, err := gormzaptest.OpenMock("postgres")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Close()
+
+	gormzapplugin.Register(m.DB, m.Logger)
+
+	m.Mock.ExpectBegin()
+	m.Mock.ExpectExec("DELETE").WillReturnError(errors.New("some serious error!"))
+	m.Mock.ExpectRollback()
+
+	m.DB.Delete(&User{ID: 1})
+
+	records := m.Records.FilterMessage("some serious error!").AllRecords()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record but got %d", len(records))
+	}
+
+	rec := records[0]
+	expected := `DELETE FROM "users"  WHERE "users"."id" = $1`
+	if rec.Statement != expected {
+		t.Fatalf("Expected statement %q but got %q", expected, rec.Statement)
+	}
+	if len(rec.Args) != 1 || rec.Args[0] != 1 {
+		t.Fatalf("Unexpected args: %v", rec.Args)
+	}
+}
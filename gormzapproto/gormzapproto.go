@@ -0,0 +1,173 @@
+// Package gormzapproto exports gormzap records as a compact stream of
+// protobuf messages instead of JSON text, for very high throughput
+// pipelines. The schema is provided in record.proto. Messages are
+// length-delimited, so the stream can be read with any protobuf library, or
+// with Reader of this package.
+//
+// Bind values are not exported, only the SQL query they are interpolated
+// into according to the logger options.
+//
+// Example usage:
+//  f, err := os.Create("queries.pb")
+//  if err != nil {
+//      panic(err)
+//  }
+//  defer f.Close()
+//  log := gormzap.New(z, gormzap.WithSinks(gormzapproto.NewSink(f)))
+package gormzapproto
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// Field numbers of Record message.
+const (
+	fieldMessage      = 1
+	fieldSource       = 2
+	fieldLevel        = 3
+	fieldError        = 4
+	fieldDuration     = 5
+	fieldSQL          = 6
+	fieldRowsAffected = 7
+	fieldStatement    = 8
+	fieldStart        = 9
+	fieldOperation    = 10
+	fieldTable        = 11
+	fieldSlow         = 12
+	fieldQueryHash    = 13
+	fieldTraceID      = 14
+	fieldSpanID       = 15
+	fieldSeq          = 16
+)
+
+// Protobuf wire types.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// Sink is gormzap.RecordSink that writes records to w as length-delimited
+// protobuf messages. Each record is written with a single Write call.
+//
+// The first write error stops the sink, and is returned by Err.
+type Sink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+// NewSink returns a new Sink writing to w. To reduce the number of system
+// calls, w can be wrapped with bufio.Writer, flushed by the caller.
+func NewSink(w io.Writer) *Sink {
+	return &Sink{w: w}
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (s *Sink) WriteRecord(r gormzap.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+
+	s.buf = appendDelimited(s.buf[:0], r)
+	_, s.err = s.w.Write(s.buf)
+}
+
+// Err returns the first write error of the sink, if any.
+func (s *Sink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Marshal returns record encoded as Record message, e.g. to send it over
+// another transport.
+func Marshal(r gormzap.Record) []byte {
+	return appendRecord(nil, r)
+}
+
+// appendDelimited appends record message prefixed with its size to dst. The
+// message is appended after room for the largest size, and moved next to the
+// size once it is known, so that no buffer is allocated if dst has capacity.
+func appendDelimited(dst []byte, r gormzap.Record) []byte {
+	start := len(dst)
+	dst = append(dst, make([]byte, binary.MaxVarintLen64)...)
+	dst = appendRecord(dst, r)
+
+	size := len(dst) - start - binary.MaxVarintLen64
+	n := binary.PutUvarint(dst[start:], uint64(size))
+	copy(dst[start+n:], dst[start+binary.MaxVarintLen64:])
+	return dst[:start+n+size]
+}
+
+// appendRecord appends record message to dst. Fields with zero values are
+// omitted, as in proto3.
+func appendRecord(dst []byte, r gormzap.Record) []byte {
+	dst = appendString(dst, fieldMessage, r.Message)
+	dst = appendString(dst, fieldSource, r.Source)
+	dst = appendInt(dst, fieldLevel, zigzag(int64(r.Level)))
+	if r.Err != nil {
+		dst = appendString(dst, fieldError, r.Err.Error())
+	}
+
+	if r.SQL != "" {
+		dst = appendInt(dst, fieldDuration, uint64(r.Duration))
+		dst = appendString(dst, fieldSQL, r.SQL)
+		dst = appendInt(dst, fieldRowsAffected, uint64(r.RowsAffected))
+		dst = appendString(dst, fieldStatement, r.Statement)
+		if !r.Start.IsZero() {
+			dst = appendInt(dst, fieldStart, uint64(r.Start.UnixNano()))
+		}
+		dst = appendString(dst, fieldOperation, r.Operation())
+		dst = appendString(dst, fieldTable, r.Table())
+		if r.Slow {
+			dst = appendInt(dst, fieldSlow, 1)
+		}
+		dst = appendString(dst, fieldQueryHash, r.QueryHash)
+	}
+
+	dst = appendString(dst, fieldTraceID, r.TraceID)
+	dst = appendString(dst, fieldSpanID, r.SpanID)
+	return appendInt(dst, fieldSeq, uint64(r.Seq))
+}
+
+func appendString(dst []byte, field int, s string) []byte {
+	if s == "" {
+		return dst
+	}
+	dst = appendVarint(dst, uint64(field<<3|wireBytes))
+	dst = appendVarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+func appendInt(dst []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return dst
+	}
+	dst = appendVarint(dst, uint64(field<<3|wireVarint))
+	return appendVarint(dst, v)
+}
+
+func appendVarint(dst []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(dst, buf[:n]...)
+}
+
+// zigzag encodes signed integer as sint32/sint64 value.
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// unzigzag decodes sint32/sint64 value.
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
@@ -0,0 +1,124 @@
+package gormzapproto_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapproto"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := gormzapproto.NewSink(&buf)
+
+	l := gormzap.New(zap.NewNop(), gormzap.WithSinks(sink), gormzap.WithSlowThreshold(time.Millisecond))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users WHERE id = ?", []interface{}{1}, int64(1))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+
+	if err := sink.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r := gormzapproto.NewReader(&buf)
+
+	rec, err := r.Read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rec.Start.IsZero() {
+		t.Fatalf("Expected start time")
+	}
+	rec.Start = time.Time{}
+	expected := gormzapproto.Record{
+		Message:      "gorm query",
+		Source:       "/some/file.go:34",
+		Level:        zapcore.WarnLevel,
+		Duration:     time.Millisecond * 5,
+		SQL:          "SELECT * FROM users WHERE id = 1",
+		RowsAffected: 1,
+		Statement:    "SELECT * FROM users WHERE id = ?",
+		Operation:    "SELECT",
+		Table:        "users",
+		Slow:         true,
+	}
+	if rec != expected {
+		t.Fatalf("Expected %+v but got %+v", expected, rec)
+	}
+
+	rec, err = r.Read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = gormzapproto.Record{
+		Message: "some serious error!",
+		Source:  "/some/file.go:35",
+		Level:   zapcore.ErrorLevel,
+		Error:   "some serious error!",
+	}
+	if rec != expected {
+		t.Fatalf("Expected %+v but got %+v", expected, rec)
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Expected EOF but got %v", err)
+	}
+}
+
+func TestSink_WriteRecord_allocs(t *testing.T) {
+	var buf bytes.Buffer
+	sink := gormzapproto.NewSink(&buf)
+
+	rec := gormzap.Record{Message: strings.Repeat("a", 300), Source: "/some/file.go:32", Level: zapcore.DebugLevel}
+	sink.WriteRecord(rec)
+
+	r := gormzapproto.NewReader(&buf)
+	actual, err := r.Read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual.Message != rec.Message {
+		t.Fatalf("Expected message of %d bytes but got %d", len(rec.Message), len(actual.Message))
+	}
+
+	sink = gormzapproto.NewSink(ioutil.Discard)
+	sink.WriteRecord(rec)
+	if n := testing.AllocsPerRun(100, func() { sink.WriteRecord(rec) }); n != 0 {
+		t.Fatalf("Expected no allocations but got %v", n)
+	}
+}
+
+func TestReader_Read_truncated(t *testing.T) {
+	var buf bytes.Buffer
+	gormzapproto.NewSink(&buf).WriteRecord(gormzap.Record{Message: "some message", Level: zapcore.DebugLevel})
+
+	r := gormzapproto.NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if _, err := r.Read(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Expected unexpected EOF but got %v", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	b := gormzapproto.Marshal(gormzap.Record{Message: "some message", Source: "/some/file.go:32", Level: zapcore.DebugLevel})
+
+	rec, err := gormzapproto.Unmarshal(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := gormzapproto.Record{Message: "some message", Source: "/some/file.go:32", Level: zapcore.DebugLevel}
+	if rec != expected {
+		t.Fatalf("Expected %+v but got %+v", expected, rec)
+	}
+
+	if _, err := gormzapproto.Unmarshal(b[:len(b)-1]); err == nil {
+		t.Fatalf("Expected error for truncated message")
+	}
+}
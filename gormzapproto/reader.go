@@ -0,0 +1,151 @@
+package gormzapproto
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maxMessageSize is the maximum size of a message Reader accepts.
+const maxMessageSize = 64 << 20
+
+// Record is a record read from the stream, see record.proto.
+type Record struct {
+	Message string
+	Source  string
+	Level   zapcore.Level
+	Error   string
+
+	Duration     time.Duration
+	SQL          string
+	RowsAffected int64
+	Statement    string
+	Start        time.Time
+
+	Operation string
+	Table     string
+	Slow      bool
+	QueryHash string
+
+	TraceID string
+	SpanID  string
+	Seq     int64
+}
+
+// Reader reads records written by Sink.
+type Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewReader returns a new Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read reads the next record. It returns io.EOF at the end of the stream,
+// and io.ErrUnexpectedEOF if the stream ends in the middle of a record.
+func (r *Reader) Read() (Record, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return Record{}, err
+	}
+	if size > maxMessageSize {
+		return Record{}, fmt.Errorf("gormzapproto: message size %d exceeds limit", size)
+	}
+
+	if uint64(cap(r.buf)) < size {
+		r.buf = make([]byte, size)
+	}
+	r.buf = r.buf[:size]
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+
+	return Unmarshal(r.buf)
+}
+
+var errMalformed = errors.New("gormzapproto: malformed message")
+
+// Unmarshal parses Record message. Unknown fields are skipped.
+func Unmarshal(b []byte) (Record, error) {
+	var rec Record
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return Record{}, errMalformed
+		}
+		b = b[n:]
+
+		field, wire := int(key>>3), int(key&7)
+		switch wire {
+		case wireVarint:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return Record{}, errMalformed
+			}
+			b = b[n:]
+			rec.setInt(field, v)
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return Record{}, errMalformed
+			}
+			rec.setString(field, string(b[n:n+int(size)]))
+			b = b[n+int(size):]
+		default:
+			return Record{}, fmt.Errorf("gormzapproto: unsupported wire type %d", wire)
+		}
+	}
+	return rec, nil
+}
+
+func (r *Record) setInt(field int, v uint64) {
+	switch field {
+	case fieldLevel:
+		r.Level = zapcore.Level(unzigzag(v))
+	case fieldDuration:
+		r.Duration = time.Duration(v)
+	case fieldRowsAffected:
+		r.RowsAffected = int64(v)
+	case fieldStart:
+		r.Start = time.Unix(0, int64(v))
+	case fieldSlow:
+		r.Slow = v != 0
+	case fieldSeq:
+		r.Seq = int64(v)
+	}
+}
+
+func (r *Record) setString(field int, s string) {
+	switch field {
+	case fieldMessage:
+		r.Message = s
+	case fieldSource:
+		r.Source = s
+	case fieldError:
+		r.Error = s
+	case fieldSQL:
+		r.SQL = s
+	case fieldStatement:
+		r.Statement = s
+	case fieldOperation:
+		r.Operation = s
+	case fieldTable:
+		r.Table = s
+	case fieldQueryHash:
+		r.QueryHash = s
+	case fieldTraceID:
+		r.TraceID = s
+	case fieldSpanID:
+		r.SpanID = s
+	}
+}
@@ -0,0 +1,308 @@
+package gormzapsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// conn wraps driver.Conn, implementing optional driver interfaces by calling
+// the wrapped conn when it implements them, and falling back to what
+// database/sql does otherwise, including the deprecated driver.Execer and
+// driver.Queryer.
+type conn struct {
+	conn   driver.Conn
+	logger *gormzap.Logger
+}
+
+// newConn wraps c, implementing driver.Pinger only when c does, so that the
+// wrapped conn advertises the same capabilities as c.
+func newConn(c driver.Conn, l *gormzap.Logger) driver.Conn {
+	cn := &conn{conn: c, logger: l}
+	if _, ok := c.(driver.Pinger); ok {
+		return &pingerConn{cn}
+	}
+	return cn
+}
+
+// pingerConn is conn wrapping driver.Pinger.
+type pingerConn struct {
+	*conn
+}
+
+// Ping implements driver.Pinger.
+func (c *pingerConn) Ping(ctx context.Context) error {
+	return c.conn.conn.(driver.Pinger).Ping(ctx)
+}
+
+// Prepare implements driver.Conn.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{stmt: s, query: query, logger: c.logger}, nil
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	s, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{stmt: s, query: query, logger: c.logger}, nil
+}
+
+// Close implements driver.Conn.
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin implements driver.Conn.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errIsolationLevel
+	}
+	if opts.ReadOnly {
+		return nil, errReadOnly
+	}
+	tx, err := c.Begin()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		tx.Rollback()
+		return nil, ctx.Err()
+	default:
+		return tx, nil
+	}
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return c.exec(ctx, query, args)
+	}
+	q := newQuery(ctx, c.logger, query, args)
+	res, err := ec.ExecContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	q.log(rowsAffected(res, err), err)
+	return res, err
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return c.query(ctx, query, args)
+	}
+	q := newQuery(ctx, c.logger, query, args)
+	r, err := qc.QueryContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	if err != nil {
+		q.log(0, err)
+		return nil, err
+	}
+	return &rows{rows: r, query: q}, nil
+}
+
+// exec executes the query with the deprecated driver.Execer, as database/sql
+// does for drivers not implementing driver.ExecerContext.
+func (c *conn) exec(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.conn.(driver.Execer)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	values, err := driverValues(args)
+	if err != nil {
+		return nil, err
+	}
+	q := newQuery(ctx, c.logger, query, args)
+	res, err := e.Exec(query, values)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	q.log(rowsAffected(res, err), err)
+	return res, err
+}
+
+// query queries with the deprecated driver.Queryer, as database/sql does for
+// drivers not implementing driver.QueryerContext.
+func (c *conn) query(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qr, ok := c.conn.(driver.Queryer)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	values, err := driverValues(args)
+	if err != nil {
+		return nil, err
+	}
+	q := newQuery(ctx, c.logger, query, args)
+	r, err := qr.Query(query, values)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	if err != nil {
+		q.log(0, err)
+		return nil, err
+	}
+	return &rows{rows: r, query: q}, nil
+}
+
+// ResetSession implements driver.SessionResetter.
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid implements driver.Validator.
+func (c *conn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (c *conn) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := c.conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+// stmt wraps driver.Stmt the same way conn wraps driver.Conn.
+type stmt struct {
+	stmt   driver.Stmt
+	query  string
+	logger *gormzap.Logger
+}
+
+// Close implements driver.Stmt.
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+// NumInput implements driver.Stmt.
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// Exec implements driver.Stmt.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.exec(context.Background(), args)
+}
+
+// Query implements driver.Stmt.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.queryValues(context.Background(), args)
+}
+
+// exec executes the statement with driver.Stmt, logging it bound to ctx.
+func (s *stmt) exec(ctx context.Context, args []driver.Value) (driver.Result, error) {
+	q := newQuery(ctx, s.logger, s.query, namedValues(args))
+	res, err := s.stmt.Exec(args)
+	q.log(rowsAffected(res, err), err)
+	return res, err
+}
+
+// queryValues queries the statement with driver.Stmt, logging it bound to
+// ctx.
+func (s *stmt) queryValues(ctx context.Context, args []driver.Value) (driver.Rows, error) {
+	q := newQuery(ctx, s.logger, s.query, namedValues(args))
+	r, err := s.stmt.Query(args)
+	if err != nil {
+		q.log(0, err)
+		return nil, err
+	}
+	return &rows{rows: r, query: q}, nil
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := s.stmt.(driver.StmtExecContext)
+	if !ok {
+		values, err := driverValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.exec(ctx, values)
+	}
+	q := newQuery(ctx, s.logger, s.query, args)
+	res, err := ec.ExecContext(ctx, args)
+	q.log(rowsAffected(res, err), err)
+	return res, err
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := s.stmt.(driver.StmtQueryContext)
+	if !ok {
+		values, err := driverValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.queryValues(ctx, values)
+	}
+	q := newQuery(ctx, s.logger, s.query, args)
+	r, err := qc.QueryContext(ctx, args)
+	if err != nil {
+		q.log(0, err)
+		return nil, err
+	}
+	return &rows{rows: r, query: q}, nil
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (s *stmt) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+// driverValues converts named values for drivers not supporting them, as
+// database/sql does.
+func driverValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errNamedArgs
+		}
+		values[i] = a.Value
+	}
+	return values, nil
+}
+
+func rowsAffected(res driver.Result, err error) int64 {
+	if err != nil || res == nil {
+		return 0
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,172 @@
+// Package gormzapsql wraps database/sql drivers to log queries with gormzap
+// logger, so that queries made with raw database/sql or sqlx are logged with
+// the same records as those made with gorm.
+//
+// Example usage:
+//  sql.Register("postgres-gormzap", gormzapsql.Wrap(&pq.Driver{}, log))
+//  db, err := sql.Open("postgres-gormzap", dsn)
+//
+// Queries are logged when they complete: Exec calls when they return, and
+// Query calls when their rows are closed, with the number of rows read as
+// rows affected. Failed queries are preceded by an error record carrying the
+// failing statement, as gorm does. Both records are bound to the query
+// context, see gormzap.Logger.WithContext, when the query is made with one.
+//
+// For drivers already wrapped with sqlhooks, see Hooks.
+package gormzapsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+var (
+	errNamedArgs      = errors.New("gormzapsql: driver does not support the use of Named Parameters")
+	errIsolationLevel = errors.New("gormzapsql: driver does not support non-default isolation level")
+	errReadOnly       = errors.New("gormzapsql: driver does not support read-only transactions")
+)
+
+// Wrap returns a driver logging queries of d with l.
+func Wrap(d driver.Driver, l *gormzap.Logger) driver.Driver {
+	return &wrappedDriver{driver: d, logger: l}
+}
+
+// WrapConnector returns a connector logging queries of c with l, e.g. to be
+// used with sql.OpenDB.
+func WrapConnector(c driver.Connector, l *gormzap.Logger) driver.Connector {
+	return &connector{connector: c, driver: Wrap(c.Driver(), l), logger: l}
+}
+
+type wrappedDriver struct {
+	driver driver.Driver
+	logger *gormzap.Logger
+}
+
+// Open implements driver.Driver.
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(c, d.logger), nil
+}
+
+type connector struct {
+	connector driver.Connector
+	driver    driver.Driver
+	logger    *gormzap.Logger
+}
+
+// Connect implements driver.Connector.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(cn, c.logger), nil
+}
+
+// Driver implements driver.Connector.
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// query is a query being made, logged when it completes.
+type query struct {
+	ctx    context.Context
+	logger *gormzap.Logger
+	source string
+	start  time.Time
+	sql    string
+	args   []interface{}
+}
+
+func newQuery(ctx context.Context, l *gormzap.Logger, sql string, args []driver.NamedValue) *query {
+	return &query{
+		ctx:    ctx,
+		logger: l,
+		source: source(l),
+		start:  time.Now(),
+		sql:    sql,
+		args:   namedArgs(args),
+	}
+}
+
+// log logs the completed query bound to its context, preceded by an error
+// record if it failed. Queries skipped with driver.ErrSkip are not logged, as
+// database/sql retries them another way.
+func (q *query) log(rowsAffected int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	q.logger.LogQueryFrom(q.ctx, q.source, q.sql, q.args, time.Since(q.start), rowsAffected, err)
+}
+
+// namedArgs returns bind values as gormzap expects them: named values as
+// sql.NamedArg, and the rest as is.
+func namedArgs(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			values[i] = sql.Named(a.Name, a.Value)
+			continue
+		}
+		values[i] = a.Value
+	}
+	return values
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	values := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		values[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return values
+}
+
+// skipSources are prefixes of functions skipped when looking for the source
+// of a query.
+var skipSources = []string{
+	"database/sql.",
+	"github.com/hypnoglow/gormzap/gormzapsql.",
+	"github.com/qustavo/sqlhooks",
+}
+
+// source returns file and line of the first caller outside of database/sql
+// and driver wrappers, like gorm does for its queries, or empty string if l
+// does not log sources.
+func source(l *gormzap.Logger) string {
+	if !l.SourceEnabled() {
+		return ""
+	}
+
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !skipSource(f.Function) {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func skipSource(function string) bool {
+	for _, prefix := range skipSources {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
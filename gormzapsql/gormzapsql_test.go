@@ -0,0 +1,348 @@
+package gormzapsql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapsql"
+)
+
+func TestWrap(t *testing.T) {
+	d := &fakeDriver{}
+	l, records := observer()
+	db := sql.OpenDB(dsnConnector{gormzapsql.Wrap(d, l), ""})
+	defer db.Close()
+
+	t.Run("exec", func(t *testing.T) {
+		d.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+			return driver.RowsAffected(1), nil
+		}
+
+		if _, err := db.Exec("UPDATE users SET name = $1 WHERE id = $2", "Jane", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		recs := records.TakeAll()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		rec := recs[0]
+		if rec.SQL != "UPDATE users SET name = 'Jane' WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", rec.SQL)
+		}
+		if rec.Statement != "UPDATE users SET name = $1 WHERE id = $2" {
+			t.Errorf("unexpected statement: %q", rec.Statement)
+		}
+		if rec.RowsAffected != 1 {
+			t.Errorf("expected 1 row affected, got %d", rec.RowsAffected)
+		}
+		if !strings.Contains(rec.Source, "gormzapsql_test.go:") {
+			t.Errorf("expected source in the test file, got %q", rec.Source)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		d.query = func(string, []driver.NamedValue) (driver.Rows, error) {
+			return &fakeRows{columns: []string{"name"}, values: [][]driver.Value{{"Jane"}, {"John"}}}, nil
+		}
+
+		rows, err := db.Query("SELECT name FROM users LIMIT $1", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rows.Next() {
+			t.Fatalf("expected rows")
+		}
+		if records.Len() != 0 {
+			t.Fatalf("expected query to be logged on rows close")
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		recs := records.TakeAll()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		if recs[0].SQL != "SELECT name FROM users LIMIT 10" {
+			t.Errorf("unexpected SQL: %q", recs[0].SQL)
+		}
+		if recs[0].RowsAffected != 1 {
+			t.Errorf("expected 1 row read, got %d", recs[0].RowsAffected)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		d.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+			return nil, errors.New("permission denied")
+		}
+
+		if _, err := db.Exec("DELETE FROM users WHERE id = $1", 1); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		recs := records.TakeAll()
+		if len(recs) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(recs))
+		}
+		if recs[0].Level != zapcore.ErrorLevel || recs[0].Message != "permission denied" {
+			t.Errorf("unexpected error record: %s %q", recs[0].Level, recs[0].Message)
+		}
+		if recs[0].Statement != "DELETE FROM users WHERE id = $1" {
+			t.Errorf("unexpected error record statement: %q", recs[0].Statement)
+		}
+		if recs[1].SQL != "DELETE FROM users WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", recs[1].SQL)
+		}
+	})
+}
+
+func TestWrapConnector_context(t *testing.T) {
+	type traceKey struct{}
+	l, records := observer(gormzap.WithTraceIDs(func(ctx context.Context) (string, string) {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id, ""
+	}))
+	d := &fakeDriver{
+		exec: func(string, []driver.NamedValue) (driver.Result, error) {
+			return nil, errors.New("permission denied")
+		},
+	}
+	db := sql.OpenDB(gormzapsql.WrapConnector(dsnConnector{d, ""}, l))
+	defer db.Close()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	if _, err := db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", 1); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	recs := records.TakeAll()
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	for i, rec := range recs {
+		if rec.TraceID != "trace-1" {
+			t.Errorf("record %d: expected trace ID from the query context, got %q", i, rec.TraceID)
+		}
+	}
+}
+
+// dsnConnector is a driver.Connector opening connections of a driver that
+// does not implement driver.DriverContext. Unlike sql.Register, it can be
+// used by tests running more than once.
+type dsnConnector struct {
+	d   driver.Driver
+	dsn string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.d
+}
+
+func TestWrap_legacyConn(t *testing.T) {
+	l, records := observer()
+	c, err := gormzapsql.Wrap(legacyDriver{}, l).Open("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := c.(driver.Pinger); ok {
+		t.Errorf("expected conn not to implement driver.Pinger")
+	}
+
+	db := sql.OpenDB(dsnConnector{gormzapsql.Wrap(legacyDriver{}, l), ""})
+	defer db.Close()
+
+	t.Run("exec", func(t *testing.T) {
+		if _, err := db.Exec("UPDATE users SET name = $1", "Jane"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		recs := records.TakeAll()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		if recs[0].SQL != "UPDATE users SET name = 'Jane'" {
+			t.Errorf("unexpected SQL: %q", recs[0].SQL)
+		}
+		if recs[0].RowsAffected != 2 {
+			t.Errorf("expected 2 rows affected, got %d", recs[0].RowsAffected)
+		}
+	})
+
+	t.Run("begin tx", func(t *testing.T) {
+		tests := map[string]struct {
+			opts    *sql.TxOptions
+			wantErr string
+		}{
+			"default": {},
+			"isolation level": {
+				opts:    &sql.TxOptions{Isolation: sql.LevelSerializable},
+				wantErr: "gormzapsql: driver does not support non-default isolation level",
+			},
+			"read only": {
+				opts:    &sql.TxOptions{ReadOnly: true},
+				wantErr: "gormzapsql: driver does not support read-only transactions",
+			},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				tx, err := db.BeginTx(context.Background(), tt.opts)
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					tx.Rollback()
+					return
+				}
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+			})
+		}
+	})
+}
+
+// legacyDriver is a driver implementing only driver.Conn and the deprecated
+// driver.Execer.
+type legacyDriver struct{}
+
+func (legacyDriver) Open(string) (driver.Conn, error) {
+	return legacyConn{}, nil
+}
+
+type legacyConn struct{}
+
+func (legacyConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (legacyConn) Close() error {
+	return nil
+}
+
+func (legacyConn) Begin() (driver.Tx, error) {
+	return legacyTx{}, nil
+}
+
+func (legacyConn) Exec(string, []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(2), nil
+}
+
+type legacyTx struct{}
+
+func (legacyTx) Commit() error {
+	return nil
+}
+
+func (legacyTx) Rollback() error {
+	return nil
+}
+
+// fakeDriver is a driver answering queries with funcs set by tests.
+type fakeDriver struct {
+	exec  func(query string, args []driver.NamedValue) (driver.Result, error)
+	query func(query string, args []driver.NamedValue) (driver.Rows, error)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return legacyTx{}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.d.exec(query, args)
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.d.query(query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// observer returns a logger discarding encoded logs but keeping every record
+// in the returned recordSink, as gormzaptest.New does. gormzaptest is a
+// separate module, which gormzap tests do not depend on.
+func observer(opts ...gormzap.LoggerOption) (*gormzap.Logger, *recordSink) {
+	records := &recordSink{}
+	opts = append(opts[:len(opts):len(opts)], gormzap.WithSinks(records))
+	return gormzap.New(zap.NewNop(), opts...), records
+}
+
+// recordSink is a concurrency-safe collection of written records.
+type recordSink struct {
+	mu      sync.Mutex
+	records []gormzap.Record
+}
+
+func (s *recordSink) WriteRecord(r gormzap.Record) {
+	s.mu.Lock()
+	s.records = append(s.records, r)
+	s.mu.Unlock()
+}
+
+func (s *recordSink) Len() int {
+	return len(s.AllRecords())
+}
+
+func (s *recordSink) AllRecords() []gormzap.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]gormzap.Record(nil), s.records...)
+}
+
+func (s *recordSink) TakeAll() []gormzap.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := s.records
+	s.records = nil
+	return ret
+}
@@ -0,0 +1,60 @@
+package gormzapsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// Hooks logs queries with gormzap logger, implementing sqlhooks.Hooks and
+// sqlhooks.OnErrorer interfaces of github.com/qustavo/sqlhooks, for those
+// already wrapping their drivers with sqlhooks.
+//
+// Example usage:
+//  sql.Register("postgres-hooks", sqlhooks.Wrap(&pq.Driver{}, gormzapsql.NewHooks(log)))
+//
+// Unlike Wrap, sqlhooks calls After as soon as Query returns, so rows
+// affected of SELECT queries are not known and logged as zero.
+type Hooks struct {
+	logger *gormzap.Logger
+}
+
+// NewHooks returns new Hooks logging queries with l.
+func NewHooks(l *gormzap.Logger) *Hooks {
+	return &Hooks{logger: l}
+}
+
+type hooksStartKey struct{}
+
+// Before implements sqlhooks.Hooks.
+func (h *Hooks) Before(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	return context.WithValue(ctx, hooksStartKey{}, time.Now()), nil
+}
+
+// After implements sqlhooks.Hooks.
+func (h *Hooks) After(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	h.query(ctx, query, args).log(0, nil)
+	return ctx, nil
+}
+
+// OnError implements sqlhooks.OnErrorer.
+func (h *Hooks) OnError(ctx context.Context, err error, query string, args ...interface{}) error {
+	h.query(ctx, query, args).log(0, err)
+	return err
+}
+
+func (h *Hooks) query(ctx context.Context, sql string, args []interface{}) *query {
+	start, ok := ctx.Value(hooksStartKey{}).(time.Time)
+	if !ok {
+		start = time.Now()
+	}
+	return &query{
+		ctx:    ctx,
+		logger: h.logger,
+		source: source(h.logger),
+		start:  start,
+		sql:    sql,
+		args:   args,
+	}
+}
@@ -0,0 +1,68 @@
+package gormzapsql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hypnoglow/gormzap/gormzapsql"
+)
+
+// sqlhooks mirrors interfaces of github.com/qustavo/sqlhooks/v2.
+type sqlhooks interface {
+	Before(ctx context.Context, query string, args ...interface{}) (context.Context, error)
+	After(ctx context.Context, query string, args ...interface{}) (context.Context, error)
+	OnError(ctx context.Context, err error, query string, args ...interface{}) error
+}
+
+var _ sqlhooks = (*gormzapsql.Hooks)(nil)
+
+func TestHooks(t *testing.T) {
+	t.Run("after", func(t *testing.T) {
+		l, records := observer()
+		h := gormzapsql.NewHooks(l)
+
+		ctx, err := h.Before(context.Background(), "SELECT * FROM users WHERE id = $1", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := h.After(ctx, "SELECT * FROM users WHERE id = $1", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		recs := records.AllRecords()
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		if recs[0].SQL != "SELECT * FROM users WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", recs[0].SQL)
+		}
+		if recs[0].Duration <= 0 {
+			t.Errorf("expected duration to be measured from Before")
+		}
+	})
+
+	t.Run("on error", func(t *testing.T) {
+		l, records := observer()
+		h := gormzapsql.NewHooks(l)
+
+		ctx, _ := h.Before(context.Background(), "DELETE FROM users WHERE id = $1", 1)
+		dbErr := errors.New("permission denied")
+		if err := h.OnError(ctx, dbErr, "DELETE FROM users WHERE id = $1", 1); err != dbErr {
+			t.Fatalf("expected the error to be returned, got %v", err)
+		}
+
+		recs := records.AllRecords()
+		if len(recs) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(recs))
+		}
+		if recs[0].Level != zapcore.ErrorLevel || recs[0].Statement != "DELETE FROM users WHERE id = $1" {
+			t.Errorf("unexpected error record: %s %q", recs[0].Level, recs[0].Statement)
+		}
+		if recs[1].SQL != "DELETE FROM users WHERE id = 1" {
+			t.Errorf("unexpected SQL: %q", recs[1].SQL)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Package gormzapstatsd sends StatsD metrics of queries logged with gormzap:
+// query duration and count, and error count. Metrics can be tagged with
+// statement operation and table in DogStatsD format.
+//
+// Example usage:
+//  sink, err := gormzapstatsd.NewSink("127.0.0.1:8125", gormzapstatsd.WithDogStatsDTags())
+//  if err != nil {
+//      panic(err)
+//  }
+//  defer sink.Close()
+//  log := gormzap.New(z, gormzap.WithSinks(sink))
+package gormzapstatsd
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultPrefix is the default prefix of metric names.
+const DefaultPrefix = "gormzap."
+
+// DefaultMaxTables is the default maximum number of distinct table tags.
+const DefaultMaxTables = 100
+
+// Sink is gormzap.RecordSink that sends StatsD metrics of the records:
+//  gormzap.query.duration:5|ms
+//  gormzap.query.count:1|c
+//  gormzap.errors:1|c
+//
+// Metrics of a record are sent in a single packet. Write errors are ignored,
+// as metrics are sent over UDP on the query goroutine.
+type Sink struct {
+	w      io.Writer
+	prefix string
+	tags   bool
+
+	maxTables int
+	tables    *gormzap.TableLabeler
+}
+
+// Option configures Sink.
+type Option func(*Sink)
+
+// WithPrefix returns Sink option that sets prefix of metric names.
+// By default, DefaultPrefix is used.
+func WithPrefix(prefix string) Option {
+	return func(s *Sink) {
+		s.prefix = prefix
+	}
+}
+
+// WithDogStatsDTags returns Sink option that tags query metrics with
+// statement operation and table in DogStatsD format. Operation is one of the
+// gormzap.OperationLabel values, and number of distinct table tags is
+// limited, see WithMaxTables. Example metric:
+//  gormzap.query.duration:5|ms|#operation:select,table:users
+func WithDogStatsDTags() Option {
+	return func(s *Sink) {
+		s.tags = true
+	}
+}
+
+// WithMaxTables returns Sink option that sets maximum number of distinct
+// table tags. Queries to tables beyond the limit are tagged with
+// gormzap.OtherTable. By default, DefaultMaxTables is used.
+func WithMaxTables(n int) Option {
+	return func(s *Sink) {
+		s.maxTables = n
+	}
+}
+
+// NewSink returns a new Sink sending metrics over UDP to addr.
+func NewSink(addr string, opts ...Option) (*Sink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewSinkWithWriter(conn, opts...), nil
+}
+
+// NewSinkWithWriter returns a new Sink writing metric packets to w. w must be
+// safe for concurrent use.
+func NewSinkWithWriter(w io.Writer, opts ...Option) *Sink {
+	s := &Sink{
+		w:         w,
+		prefix:    DefaultPrefix,
+		maxTables: DefaultMaxTables,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	s.tables = gormzap.NewTableLabeler(s.maxTables)
+	return s
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (s *Sink) WriteRecord(r gormzap.Record) {
+	var buf []byte
+
+	if r.SQL != "" {
+		var tags []byte
+		if s.tags {
+			tags = s.appendTags(tags, r)
+		}
+
+		buf = s.appendMetric(buf, "query.duration", strconv.FormatFloat(float64(r.Duration)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+		buf = s.appendMetric(buf, "query.count", "1", "c", tags)
+	}
+	if r.Level >= zapcore.ErrorLevel {
+		buf = s.appendMetric(buf, "errors", "1", "c", nil)
+	}
+
+	if len(buf) > 0 {
+		_, _ = s.w.Write(buf[:len(buf)-1])
+	}
+}
+
+// Close closes the underlying writer, if it is io.Closer.
+func (s *Sink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// appendMetric appends metric line terminated with newline to buf.
+func (s *Sink) appendMetric(buf []byte, name, value, typ string, tags []byte) []byte {
+	buf = append(buf, s.prefix...)
+	buf = append(buf, name...)
+	buf = append(buf, ':')
+	buf = append(buf, value...)
+	buf = append(buf, '|')
+	buf = append(buf, typ...)
+	buf = append(buf, tags...)
+	return append(buf, '\n')
+}
+
+// appendTags appends DogStatsD tags of the record to buf.
+func (s *Sink) appendTags(buf []byte, r gormzap.Record) []byte {
+	buf = append(buf, "|#operation:"...)
+	buf = append(buf, gormzap.OperationLabel(r)...)
+	if table := s.tables.Label(r.Table()); table != "" {
+		buf = append(buf, ",table:"...)
+		buf = append(buf, tagValue(table)...)
+	}
+	return buf
+}
+
+// tagReplacer replaces characters reserved in DogStatsD format.
+var tagReplacer = strings.NewReplacer("|", "_", ",", "_", "#", "_", "\n", "_")
+
+// tagValue returns s usable as a tag value, see tagReplacer.
+func tagValue(s string) string {
+	return tagReplacer.Replace(s)
+}
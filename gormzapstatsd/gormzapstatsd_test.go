@@ -0,0 +1,75 @@
+package gormzapstatsd_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapstatsd"
+	"go.uber.org/zap"
+)
+
+type packets struct {
+	mu  sync.Mutex
+	all []string
+}
+
+func (p *packets) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.all = append(p.all, string(b))
+	return len(b), nil
+}
+
+func TestSink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []gormzapstatsd.Option
+		expected []string
+	}{
+		{
+			name: "default",
+			expected: []string{
+				"gormzap.query.duration:1.5|ms\ngormzap.query.count:1|c",
+				"gormzap.errors:1|c",
+			},
+		},
+		{
+			name: "dogstatsd tags",
+			opts: []gormzapstatsd.Option{gormzapstatsd.WithPrefix("app.db."), gormzapstatsd.WithDogStatsDTags()},
+			expected: []string{
+				"app.db.query.duration:1.5|ms|#operation:select,table:users\napp.db.query.count:1|c|#operation:select,table:users",
+				"app.db.errors:1|c",
+			},
+		},
+		{
+			name: "max tables",
+			opts: []gormzapstatsd.Option{gormzapstatsd.WithDogStatsDTags(), gormzapstatsd.WithMaxTables(0)},
+			expected: []string{
+				"gormzap.query.duration:1.5|ms|#operation:select,table:other\ngormzap.query.count:1|c|#operation:select,table:other",
+				"gormzap.errors:1|c",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &packets{}
+			l := gormzap.New(zap.NewNop(), gormzap.WithSinks(gormzapstatsd.NewSinkWithWriter(p, tc.opts...)))
+
+			l.Print("sql", "/some/file.go:34", time.Microsecond*1500, "SELECT * FROM users", []interface{}{}, int64(1))
+			l.Print("/some/file.go:32", errors.New("some serious error!"))
+
+			if len(p.all) != len(tc.expected) {
+				t.Fatalf("Expected %d packets but got %d: %q", len(tc.expected), len(p.all), p.all)
+			}
+			for i, e := range tc.expected {
+				if p.all[i] != e {
+					t.Fatalf("Expected %q but got %q", e, p.all[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/hypnoglow/gormzap/gormzaptest
+
+go 1.16
+
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/hypnoglow/gormzap v0.0.0-20261014181348-8c9642c5caf3
+	github.com/jinzhu/gorm v1.9.16
+	go.uber.org/zap v1.8.0
+)
+
+// Local development against the gormzap module in the parent directory.
+replace github.com/hypnoglow/gormzap => ../
@@ -0,0 +1,114 @@
+// Package gormzaptest provides helpers for testing code that logs with
+// gormzap, allowing to assert on logged records without parsing encoded logs.
+//
+// Example usage:
+//  log, records := gormzaptest.New()
+//  orm.SetLogger(log)
+//  // ...
+//  if records.FilterSQLContains("DELETE").Len() != 1 {
+//      t.Fatal("Expected exactly one DELETE query")
+//  }
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// gorm and sqlmock, which OpenMock uses.
+package gormzaptest
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New returns a gorm logger that discards encoded logs but keeps every record
+// in the returned ObservedRecords.
+func New(opts ...gormzap.LoggerOption) (*gormzap.Logger, *ObservedRecords) {
+	o := NewObserver()
+	opts = append(opts[:len(opts):len(opts)], gormzap.WithSinks(o))
+	l := gormzap.New(zap.NewNop(), opts...)
+	return l, o
+}
+
+// NewObserver returns an empty ObservedRecords, which can be attached to any
+// logger with gormzap.WithSinks.
+func NewObserver() *ObservedRecords {
+	return &ObservedRecords{}
+}
+
+// ObservedRecords is a concurrency-safe collection of observed records.
+type ObservedRecords struct {
+	mu      sync.RWMutex
+	records []gormzap.Record
+}
+
+// WriteRecord implements gormzap.RecordSink.
+func (o *ObservedRecords) WriteRecord(r gormzap.Record) {
+	o.mu.Lock()
+	o.records = append(o.records, r)
+	o.mu.Unlock()
+}
+
+// Len returns the number of observed records.
+func (o *ObservedRecords) Len() int {
+	o.mu.RLock()
+	n := len(o.records)
+	o.mu.RUnlock()
+	return n
+}
+
+// AllRecords returns a copy of all observed records.
+func (o *ObservedRecords) AllRecords() []gormzap.Record {
+	o.mu.RLock()
+	ret := make([]gormzap.Record, len(o.records))
+	copy(ret, o.records)
+	o.mu.RUnlock()
+	return ret
+}
+
+// TakeAll returns all observed records and resets the collection.
+func (o *ObservedRecords) TakeAll() []gormzap.Record {
+	o.mu.Lock()
+	ret := o.records
+	o.records = nil
+	o.mu.Unlock()
+	return ret
+}
+
+// Filter returns a copy of the collection containing only records for which
+// keep returns true.
+func (o *ObservedRecords) Filter(keep func(gormzap.Record) bool) *ObservedRecords {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	var filtered []gormzap.Record
+	for _, r := range o.records {
+		if keep(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return &ObservedRecords{records: filtered}
+}
+
+// FilterLevel filters records with the given level.
+func (o *ObservedRecords) FilterLevel(level zapcore.Level) *ObservedRecords {
+	return o.Filter(func(r gormzap.Record) bool {
+		return r.Level == level
+	})
+}
+
+// FilterMessage filters records with the given message.
+func (o *ObservedRecords) FilterMessage(msg string) *ObservedRecords {
+	return o.Filter(func(r gormzap.Record) bool {
+		return r.Message == msg
+	})
+}
+
+// FilterSQLContains filters SQL query records which logged query contains
+// the given substring.
+func (o *ObservedRecords) FilterSQLContains(s string) *ObservedRecords {
+	return o.Filter(func(r gormzap.Record) bool {
+		return r.SQL != "" && strings.Contains(r.SQL, s)
+	})
+}
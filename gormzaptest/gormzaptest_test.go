@@ -0,0 +1,69 @@
+package gormzaptest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzaptest"
+	"go.uber.org/zap"
+)
+
+func TestObservedRecords(t *testing.T) {
+	l, records := gormzaptest.New()
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE id = $1",
+		[]interface{}{42},
+		int64(1),
+	)
+	l.Print(
+		"sql",
+		"/some/file.go:35",
+		time.Millisecond*5,
+		"DELETE FROM test WHERE id = $1",
+		[]interface{}{42},
+		int64(1),
+	)
+	l.Print("/some/file.go:36", errors.New("some serious error!"))
+
+	if records.Len() != 3 {
+		t.Fatalf("Expected 3 records but got %d", records.Len())
+	}
+
+	deletes := records.FilterSQLContains("DELETE").AllRecords()
+	if len(deletes) != 1 || deletes[0].SQL != "DELETE FROM test WHERE id = 42" {
+		t.Fatalf("Unexpected DELETE records %+v", deletes)
+	}
+
+	errs := records.FilterLevel(zap.ErrorLevel).AllRecords()
+	if len(errs) != 1 || errs[0].Message != "some serious error!" {
+		t.Fatalf("Unexpected error records %+v", errs)
+	}
+
+	if n := records.FilterMessage("gorm query").Len(); n != 2 {
+		t.Fatalf("Expected 2 query records but got %d", n)
+	}
+
+	if n := len(records.TakeAll()); n != 3 {
+		t.Fatalf("Expected to take 3 records but got %d", n)
+	}
+	if records.Len() != 0 {
+		t.Fatalf("Expected no records after TakeAll but got %d", records.Len())
+	}
+}
+
+func TestNew_doesNotModifyOptions(t *testing.T) {
+	opts := make([]gormzap.LoggerOption, 1, 2)
+	opts[0] = gormzap.WithLevel(zap.InfoLevel)
+
+	gormzaptest.New(opts...)
+
+	if extra := opts[:2][1]; extra != nil {
+		t.Fatal("Expected New not to write into the options backing array")
+	}
+}
@@ -0,0 +1,62 @@
+package gormzaptest
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hypnoglow/gormzap"
+	"github.com/jinzhu/gorm"
+)
+
+// MockDB is a gorm DB backed by sqlmock, which logs all queries with gormzap
+// logger observing its records.
+//
+// Example usage:
+//  m, err := gormzaptest.OpenMock("postgres")
+//  if err != nil {
+//      t.Fatal(err)
+//  }
+//  defer m.Close()
+//
+//  m.Mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+//  m.DB.Exec("DELETE FROM users")
+//
+//  if m.Records.FilterSQLContains("DELETE").Len() != 1 {
+//      t.Fatal("Expected DELETE query to be logged")
+//  }
+type MockDB struct {
+	DB      *gorm.DB
+	Mock    sqlmock.Sqlmock
+	Logger  *gormzap.Logger
+	Records *ObservedRecords
+}
+
+// OpenMock opens gorm DB with the given dialect name, e.g. "postgres" or
+// "mysql", backed by sqlmock. Log mode is enabled, so that every query is
+// logged, and the logger is created as New(opts...).
+func OpenMock(dialect string, opts ...gormzap.LoggerOption) (*MockDB, error) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialect, sqlDB)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	l, records := New(opts...)
+	db.LogMode(true)
+	db.SetLogger(l)
+
+	return &MockDB{
+		DB:      db,
+		Mock:    mock,
+		Logger:  l,
+		Records: records,
+	}, nil
+}
+
+// Close closes the DB.
+func (m *MockDB) Close() error {
+	return m.DB.Close()
+}
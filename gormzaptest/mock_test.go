@@ -0,0 +1,48 @@
+package gormzaptest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hypnoglow/gormzap/gormzaptest"
+	"go.uber.org/zap"
+)
+
+func TestOpenMock(t *testing.T) {
+	m, err := gormzaptest.OpenMock("postgres")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Close()
+
+	m.Mock.ExpectExec(`DELETE FROM test WHERE id = \$1`).
+		WithArgs(42).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.Mock.ExpectExec(`DELETE FROM missing`).
+		WillReturnError(errors.New("relation does not exist"))
+
+	if err := m.DB.Exec("DELETE FROM test WHERE id = $1", 42).Error; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.DB.Exec("DELETE FROM missing").Error; err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+
+	if err := m.Mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	deletes := m.Records.FilterSQLContains("DELETE FROM test").AllRecords()
+	if len(deletes) != 1 {
+		t.Fatalf("Expected 1 DELETE record but got %d", len(deletes))
+	}
+	if deletes[0].SQL != "DELETE FROM test WHERE id = 42" || deletes[0].RowsAffected != 1 {
+		t.Fatalf("Unexpected DELETE record %+v", deletes[0])
+	}
+
+	errs := m.Records.FilterLevel(zap.ErrorLevel).AllRecords()
+	if len(errs) == 0 || errs[0].Message != "relation does not exist" {
+		t.Fatalf("Unexpected error records %+v", errs)
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/hypnoglow/gormzap/gormzapvitess
+
+go 1.26.7
+
+require (
+	github.com/hypnoglow/gormzap v0.0.0-20261014182136-c68ad8d6f5db
+	go.uber.org/zap v1.27.1
+	vitess.io/vitess v0.24.3
+)
+
+require (
+	github.com/golang/glog v1.2.5 // indirect
+	github.com/lmittmann/tint v1.1.3 // indirect
+	github.com/mattn/go-isatty v0.0.21 // indirect
+	github.com/planetscale/vtprotobuf v0.6.1-0.20250313105119-ba97887b0a25 // indirect
+	github.com/spf13/pflag v1.0.10 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260414002931-afd174a4e478 // indirect
+	google.golang.org/grpc v1.80.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+// Local development against the gormzap module in the parent directory.
+replace github.com/hypnoglow/gormzap => ../
@@ -0,0 +1,108 @@
+// Package gormzapvitess implements gormzap.Parser with the Vitess SQL parser,
+// for users of MySQL dialect, so that operation and tables of records are
+// extracted exactly, and joins are counted.
+//
+// Example usage:
+//  p, err := gormzapvitess.NewParser()
+//  if err != nil {
+//      panic(err)
+//  }
+//  log := gormzap.New(z, gormzap.WithParser(p))
+//
+// Normalized statements are not set, so that query hashes and error
+// fingerprints stay the same as those of the built-in inspection.
+//
+// The package is a separate module, so that gormzap itself does not depend on
+// Vitess and the Go version it requires.
+package gormzapvitess
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+// Parser parses statements with the Vitess SQL parser.
+type Parser struct {
+	parser *sqlparser.Parser
+}
+
+// NewParser returns new Parser, parsing statements of the default MySQL
+// server version of Vitess.
+func NewParser() (*Parser, error) {
+	p, err := sqlparser.New(sqlparser.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{parser: p}, nil
+}
+
+var _ gormzap.Parser = (*Parser)(nil)
+
+// Parse implements gormzap.Parser.
+func (p *Parser) Parse(sql string) (*gormzap.ParsedStatement, error) {
+	stmt, err := p.parser.Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &gormzap.ParsedStatement{Operation: operation(stmt)}
+
+	// Names of common table expressions are not tables, although they are
+	// referred to as such.
+	ctes := make(map[string]bool)
+	seen := make(map[string]bool)
+	addTable := func(t sqlparser.TableName) {
+		if t.IsEmpty() {
+			return
+		}
+		name := t.Name.String()
+		if !t.Qualifier.IsEmpty() {
+			name = t.Qualifier.String() + "." + name
+		} else if ctes[name] {
+			return
+		}
+		if !seen[name] {
+			seen[name] = true
+			ps.Tables = append(ps.Tables, name)
+		}
+	}
+
+	if ddl, ok := stmt.(sqlparser.DDLStatement); ok {
+		for _, t := range ddl.AffectedTables() {
+			addTable(t)
+		}
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CommonTableExpr:
+			ctes[node.ID.String()] = true
+		case *sqlparser.AliasedTableExpr:
+			if t, ok := node.Expr.(sqlparser.TableName); ok {
+				addTable(t)
+			}
+		case *sqlparser.JoinTableExpr:
+			ps.Joins++
+		}
+		return true, nil
+	}, stmt)
+
+	return ps, nil
+}
+
+// operation returns the keyword of the statement, or empty string for
+// statements which Vitess does not classify by their keyword, e.g. DDL, so
+// that it is inspected by gormzap.
+func operation(stmt sqlparser.Statement) string {
+	switch typ := sqlparser.ASTToStatementType(stmt); typ {
+	case sqlparser.StmtDDL, sqlparser.StmtOther, sqlparser.StmtUnknown:
+		return ""
+	default:
+		if op := typ.String(); !strings.Contains(op, "_") {
+			return op
+		}
+		return ""
+	}
+}
@@ -0,0 +1,97 @@
+package gormzapvitess_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hypnoglow/gormzap"
+	"github.com/hypnoglow/gormzap/gormzapvitess"
+)
+
+func TestParser_Parse(t *testing.T) {
+	p, err := gormzapvitess.NewParser()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		sql  string
+		want gormzap.ParsedStatement
+	}{
+		"select": {
+			sql:  "SELECT * FROM users WHERE id = ?",
+			want: gormzap.ParsedStatement{Operation: "SELECT", Tables: []string{"users"}},
+		},
+		"joins": {
+			sql: "SELECT u.name FROM users u JOIN accounts a ON a.user_id = u.id LEFT JOIN orders o ON o.account_id = a.id",
+			want: gormzap.ParsedStatement{
+				Operation: "SELECT",
+				Tables:    []string{"users", "accounts", "orders"},
+				Joins:     2,
+			},
+		},
+		"subquery": {
+			sql:  "SELECT * FROM users WHERE id IN (SELECT user_id FROM accounts)",
+			want: gormzap.ParsedStatement{Operation: "SELECT", Tables: []string{"users", "accounts"}},
+		},
+		"common table expression": {
+			sql:  "WITH active AS (SELECT * FROM users WHERE active) SELECT * FROM active",
+			want: gormzap.ParsedStatement{Operation: "SELECT", Tables: []string{"users"}},
+		},
+		"qualified table": {
+			sql:  "UPDATE app.users SET name = 'Jane' WHERE id = 1",
+			want: gormzap.ParsedStatement{Operation: "UPDATE", Tables: []string{"app.users"}},
+		},
+		"insert": {
+			sql:  "INSERT INTO users (name) VALUES ('Jane')",
+			want: gormzap.ParsedStatement{Operation: "INSERT", Tables: []string{"users"}},
+		},
+		"ddl": {
+			sql:  "CREATE TABLE users (id int)",
+			want: gormzap.ParsedStatement{Tables: []string{"users"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, err := p.Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*ps, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, *ps)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := p.Parse("SELEKT 1"); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestParser_withParser(t *testing.T) {
+	p, err := gormzapvitess.NewParser()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var recs []gormzap.Record
+	l := gormzap.New(zap.NewNop(), gormzap.WithParser(p), gormzap.WithSinks(gormzap.RecordSinkFunc(func(r gormzap.Record) {
+		recs = append(recs, r)
+	})))
+	l.Print("sql", "/some/file.go:34", time.Millisecond, "CREATE TABLE IF NOT EXISTS users (id int)", []interface{}{}, int64(0))
+
+	if len(recs) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(recs))
+	}
+	if op := recs[0].Operation(); op != "CREATE" {
+		t.Errorf("expected operation inspected by gormzap, got %q", op)
+	}
+	if table := recs[0].Table(); table != "users" {
+		t.Errorf("expected table users, got %q", table)
+	}
+}
@@ -0,0 +1,38 @@
+package gormzap
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// WithGoroutineID returns Logger option that logs ID of the goroutine the
+// query is logged from as "sql.goroutine" field. gorm logs queries from the
+// goroutine they are made from, so this helps to debug connection pool
+// contention and interleaving of queries.
+//
+// Go does not expose goroutine IDs, so it is parsed from the stack trace on
+// every query, which is relatively expensive. Use this option for debugging
+// only.
+func WithGoroutineID() LoggerOption {
+	return func(l *Logger) {
+		l.goroutineID = true
+	}
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns ID of the current goroutine, or zero if it cannot be
+// parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+
+	// The stack trace starts with "goroutine 123 [running]:".
+	b = bytes.TrimPrefix(b, goroutinePrefix)
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
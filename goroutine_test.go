@@ -0,0 +1,35 @@
+package gormzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	l, records := observer(gormzap.WithGoroutineID())
+
+	done := make(chan struct{})
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	go func() {
+		defer close(done)
+		l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 2", []interface{}{}, int64(1))
+	}()
+	<-done
+
+	recs := records.AllRecords()
+	var ids [2]uint64
+	for i, rec := range recs {
+		if len(rec.Fields) != 1 || rec.Fields[0].Key != "sql.goroutine" {
+			t.Fatalf("Expected sql.goroutine field but got %v", rec.Fields)
+		}
+		ids[i] = uint64(rec.Fields[0].Integer)
+		if ids[i] == 0 {
+			t.Fatalf("Expected goroutine ID but got zero")
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Fatalf("Expected different goroutine IDs but got %d", ids[0])
+	}
+}
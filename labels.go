@@ -0,0 +1,98 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OtherTable is the label TableLabeler uses for tables beyond its limit.
+const OtherTable = "other"
+
+// TableLabeler maps table names to metric labels with bounded cardinality:
+// the first max distinct tables are labeled with their names, and the rest
+// with OtherTable. It is safe for concurrent use.
+type TableLabeler struct {
+	tables *boundedSet
+}
+
+// NewTableLabeler returns a new TableLabeler labeling at most max distinct
+// tables by name. If max is zero or negative, all tables are labeled as
+// OtherTable.
+func NewTableLabeler(max int) *TableLabeler {
+	return &TableLabeler{
+		tables: newBoundedSet(max),
+	}
+}
+
+// Label returns label for the table. Empty table name is returned as is.
+func (t *TableLabeler) Label(table string) string {
+	if table == "" {
+		return ""
+	}
+	if !t.tables.add(table) {
+		return OtherTable
+	}
+	return table
+}
+
+// boundedSet is a set of at most max strings, which is safe for concurrent
+// use. Lookups do not take locks and its size is counted with atomics, so
+// that it does not serialize queries.
+type boundedSet struct {
+	n      int64
+	max    int64
+	values sync.Map
+}
+
+func newBoundedSet(max int) *boundedSet {
+	return &boundedSet{max: int64(max)}
+}
+
+// add adds s to the set unless it is full, and reports whether s is in the
+// set.
+func (b *boundedSet) add(s string) bool {
+	if _, ok := b.values.Load(s); ok {
+		return true
+	}
+	if atomic.LoadInt64(&b.n) >= b.max {
+		return false
+	}
+	if atomic.AddInt64(&b.n, 1) > b.max {
+		atomic.AddInt64(&b.n, -1)
+		_, ok := b.values.Load(s)
+		return ok
+	}
+	if _, loaded := b.values.LoadOrStore(s, struct{}{}); loaded {
+		atomic.AddInt64(&b.n, -1)
+	}
+	return true
+}
+
+// Operation labels returned by OperationLabel.
+const (
+	OperationSelect = "select"
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationDDL    = "ddl"
+	OperationOther  = "other"
+)
+
+// OperationLabel returns metric label of the record statement operation: one
+// of select, insert, update, delete, ddl or other. REPLACE statements are
+// labeled as insert.
+func OperationLabel(r Record) string {
+	switch op := r.Operation(); op {
+	case "SELECT":
+		return OperationSelect
+	case "INSERT", "REPLACE":
+		return OperationInsert
+	case "UPDATE":
+		return OperationUpdate
+	case "DELETE":
+		return OperationDelete
+	case "CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME":
+		return OperationDDL
+	}
+	return OperationOther
+}
@@ -0,0 +1,78 @@
+package gormzap_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestTableLabeler(t *testing.T) {
+	l := gormzap.NewTableLabeler(2)
+
+	for _, tc := range []struct {
+		table    string
+		expected string
+	}{
+		{table: "users", expected: "users"},
+		{table: "orders", expected: "orders"},
+		{table: "users", expected: "users"},
+		{table: "items", expected: gormzap.OtherTable},
+		{table: "", expected: ""},
+		{table: "orders", expected: "orders"},
+	} {
+		if actual := l.Label(tc.table); actual != tc.expected {
+			t.Fatalf("Expected %q for %q but got %q", tc.expected, tc.table, actual)
+		}
+	}
+}
+
+func TestTableLabeler_concurrent(t *testing.T) {
+	l := gormzap.NewTableLabeler(10)
+
+	var wg sync.WaitGroup
+	labels := make([]map[string]bool, 8)
+	for i := range labels {
+		labels[i] = make(map[string]bool)
+		wg.Add(1)
+		go func(labels map[string]bool) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				labels[l.Label("table"+strconv.Itoa(j))] = true
+			}
+		}(labels[i])
+	}
+	wg.Wait()
+
+	named := make(map[string]bool)
+	for _, ls := range labels {
+		for label := range ls {
+			if label != gormzap.OtherTable {
+				named[label] = true
+			}
+		}
+	}
+	if len(named) != 10 {
+		t.Fatalf("Expected 10 tables labeled by name but got %d", len(named))
+	}
+}
+
+func TestOperationLabel(t *testing.T) {
+	for _, tc := range []struct {
+		sql      string
+		expected string
+	}{
+		{sql: "SELECT 1", expected: gormzap.OperationSelect},
+		{sql: "insert into users values (1)", expected: gormzap.OperationInsert},
+		{sql: "REPLACE INTO users VALUES (1)", expected: gormzap.OperationInsert},
+		{sql: "UPDATE users SET name = 'foo'", expected: gormzap.OperationUpdate},
+		{sql: "DELETE FROM users", expected: gormzap.OperationDelete},
+		{sql: "ALTER TABLE users ADD COLUMN age int", expected: gormzap.OperationDDL},
+		{sql: "VACUUM", expected: gormzap.OperationOther},
+	} {
+		if actual := gormzap.OperationLabel(gormzap.Record{Statement: tc.sql}); actual != tc.expected {
+			t.Fatalf("Expected %q for %q but got %q", tc.expected, tc.sql, actual)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package gormzap
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Latency histogram buckets grow exponentially from 1µs, so that quantiles
+// are estimated within 4% relative error up to about an hour.
+const (
+	latencyMin     = time.Microsecond
+	latencyGrowth  = 1.04
+	latencyBuckets = 560
+)
+
+var latencyGrowthLog = math.Log(latencyGrowth)
+
+// LatencyTracker is a RecordSink that tracks query latency distribution in a
+// log-linear histogram, for cases where a metrics stack is not available.
+// Quantiles are estimated within 4% relative error. Recording is lock-free.
+//
+// Example usage:
+//  t := gormzap.NewLatencyTracker()
+//  log := gormzap.New(z, gormzap.WithSinks(t))
+//  stop := t.Report(log, time.Minute)
+//  defer stop()
+type LatencyTracker struct {
+	counts [latencyBuckets]int64
+	total  int64
+}
+
+// NewLatencyTracker returns a new LatencyTracker.
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{}
+}
+
+// WriteRecord implements RecordSink.
+func (t *LatencyTracker) WriteRecord(r Record) {
+	if r.SQL == "" {
+		return
+	}
+	atomic.AddInt64(&t.counts[latencyBucket(r.Duration)], 1)
+	atomic.AddInt64(&t.total, 1)
+}
+
+// Count returns the number of tracked queries.
+func (t *LatencyTracker) Count() int64 {
+	return atomic.LoadInt64(&t.total)
+}
+
+// Quantile returns estimated latency quantile q, e.g. 0.99 for p99, or zero
+// if no queries were tracked.
+func (t *LatencyTracker) Quantile(q float64) time.Duration {
+	var counts [latencyBuckets]int64
+	var total int64
+	for i := range counts {
+		counts[i] = atomic.LoadInt64(&t.counts[i])
+		total += counts[i]
+	}
+	return quantile(counts[:], total, q)
+}
+
+// Percentiles returns estimated p50, p95 and p99 latency.
+func (t *LatencyTracker) Percentiles() (p50, p95, p99 time.Duration) {
+	var counts [latencyBuckets]int64
+	var total int64
+	for i := range counts {
+		counts[i] = atomic.LoadInt64(&t.counts[i])
+		total += counts[i]
+	}
+	return quantile(counts[:], total, 0.5), quantile(counts[:], total, 0.95), quantile(counts[:], total, 0.99)
+}
+
+// Reset clears the tracked distribution. Queries recorded concurrently with
+// Reset may be partially kept.
+func (t *LatencyTracker) Reset() {
+	for i := range t.counts {
+		atomic.StoreInt64(&t.counts[i], 0)
+	}
+	atomic.StoreInt64(&t.total, 0)
+}
+
+// Report logs the tracked percentiles with l every interval, as
+// "sql.latency.p50", "sql.latency.p95" and "sql.latency.p99" fields along with
+// "sql.latency.count", and resets the distribution, so that every record
+// describes its interval. Nothing is logged for intervals without queries.
+//
+// Call the returned func to stop reporting.
+func (t *LatencyTracker) Report(l *Logger, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.report(l)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (t *LatencyTracker) report(l *Logger) {
+	count := t.Count()
+	if count == 0 {
+		return
+	}
+	p50, p95, p99 := t.Percentiles()
+	t.Reset()
+
+	l = l.load()
+	l.log(Record{
+		Message: "gormzap: query latency",
+		Level:   l.level,
+		Fields: []zapcore.Field{
+			zap.Int64("sql.latency.count", count),
+			zap.Duration("sql.latency.p50", p50),
+			zap.Duration("sql.latency.p95", p95),
+			zap.Duration("sql.latency.p99", p99),
+		},
+		stats: true,
+	})
+}
+
+// latencyBucket returns index of the histogram bucket for d.
+func latencyBucket(d time.Duration) int {
+	if d <= latencyMin {
+		return 0
+	}
+	i := int(math.Ceil(math.Log(float64(d)/float64(latencyMin)) / latencyGrowthLog))
+	if i >= latencyBuckets {
+		return latencyBuckets - 1
+	}
+	return i
+}
+
+// quantile returns upper bound of the bucket holding quantile q.
+func quantile(counts []int64, total int64, q float64) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(q * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, c := range counts {
+		seen += c
+		if seen >= rank {
+			return time.Duration(float64(latencyMin) * math.Pow(latencyGrowth, float64(i)))
+		}
+	}
+	return time.Duration(float64(latencyMin) * math.Pow(latencyGrowth, float64(len(counts)-1)))
+}
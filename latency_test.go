@@ -0,0 +1,66 @@
+package gormzap_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	tracker := gormzap.NewLatencyTracker()
+	counter := gormzap.NewLevelCounter()
+	records := &recordSink{}
+	l, _ := logger(gormzap.WithSinks(tracker, records), gormzap.WithLevelCounter(counter))
+
+	for i := 1; i <= 100; i++ {
+		l.Print("sql", "/some/file.go:34", time.Duration(i)*time.Millisecond, "SELECT 1", []interface{}{}, int64(1))
+	}
+
+	p50, p95, p99 := tracker.Percentiles()
+	for _, tc := range []struct {
+		name     string
+		actual   time.Duration
+		expected time.Duration
+	}{
+		{name: "p50", actual: p50, expected: 50 * time.Millisecond},
+		{name: "p95", actual: p95, expected: 95 * time.Millisecond},
+		{name: "p99", actual: p99, expected: 99 * time.Millisecond},
+		{name: "p100", actual: tracker.Quantile(1), expected: 100 * time.Millisecond},
+	} {
+		if tc.actual < tc.expected || float64(tc.actual) > float64(tc.expected)*1.04 {
+			t.Fatalf("Expected %s to be within 4%% of %s but got %s", tc.name, tc.expected, tc.actual)
+		}
+	}
+
+	records.TakeAll()
+	stop := tracker.Report(l, time.Millisecond*10)
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for records.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+
+	reports := records.FilterMessage("gormzap: query latency").AllRecords()
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report but got %d", len(reports))
+	}
+	keys := make([]string, 0, len(reports[0].Fields))
+	for _, f := range reports[0].Fields {
+		keys = append(keys, f.Key)
+	}
+	expected := "sql.latency.count,sql.latency.p50,sql.latency.p95,sql.latency.p99"
+	if actual := strings.Join(keys, ","); actual != expected {
+		t.Fatalf("Expected fields %s but got %s", expected, actual)
+	}
+	if tracker.Count() != 0 {
+		t.Fatalf("Expected tracker to be reset after report")
+	}
+	if n := counter.Count(zapcore.DebugLevel); n != 100 {
+		t.Fatalf("Expected report not to be counted but got %d debug records", n)
+	}
+}
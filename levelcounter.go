@@ -0,0 +1,169 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelCounterWindow is the number of seconds LevelCounter keeps counts for.
+const levelCounterWindow = 60
+
+const numLevels = int(zapcore.FatalLevel-zapcore.DebugLevel) + 1
+
+// LevelCounter counts records written by a logger per level, e.g. to answer
+// how many database errors were logged in the last minute. Use it with
+// WithLevelCounter, and publish the counts to the logger sinks with Report.
+//
+// Counting is lock-free, so that the counter does not become a contention
+// point for pools with many connections. Records written concurrently with
+// a bucket being reused for a new second may be missed by Recent, but are
+// always counted by Count.
+type LevelCounter struct {
+	total   [numLevels]int64
+	buckets [levelCounterWindow]levelBucket
+}
+
+// levelBucket holds counts of records written within a second.
+type levelBucket struct {
+	sec    int64
+	counts [numLevels]int64
+}
+
+// NewLevelCounter returns a new LevelCounter.
+func NewLevelCounter() *LevelCounter {
+	return &LevelCounter{}
+}
+
+// WithLevelCounter returns Logger option that counts records written by the
+// logger with c, like zap hooks would, without changing the zap logger: only
+// records written by this logger are counted, even if its zap logger is
+// shared with the application, and records of disabled levels are not
+// counted. Applying the option again, e.g. with CloneWith, replaces the
+// counter instead of adding one.
+func WithLevelCounter(c *LevelCounter) LoggerOption {
+	return func(l *Logger) {
+		l.levelCounter = c
+	}
+}
+
+func (c *LevelCounter) add(level zapcore.Level, t time.Time) {
+	i := int(level - zapcore.DebugLevel)
+	if i < 0 || i >= numLevels {
+		return
+	}
+	sec := t.Unix()
+
+	atomic.AddInt64(&c.total[i], 1)
+
+	b := &c.buckets[sec%levelCounterWindow]
+	for {
+		s := atomic.LoadInt64(&b.sec)
+		if s == sec {
+			break
+		}
+		if s > sec {
+			// The bucket is already reused for a later second.
+			return
+		}
+		if atomic.CompareAndSwapInt64(&b.sec, s, sec) {
+			for j := range b.counts {
+				atomic.StoreInt64(&b.counts[j], 0)
+			}
+			break
+		}
+	}
+	atomic.AddInt64(&b.counts[i], 1)
+}
+
+// Count returns the total number of records written with the level.
+func (c *LevelCounter) Count(level zapcore.Level) int64 {
+	i := int(level - zapcore.DebugLevel)
+	if i < 0 || i >= numLevels {
+		return 0
+	}
+
+	return atomic.LoadInt64(&c.total[i])
+}
+
+// Report logs the number of records written with each level since the
+// previous report with l every interval, as "sql.levels.<level>" fields, e.g.
+// "sql.levels.error", so that the counts reach sinks of l, e.g. metrics ones,
+// like LatencyTracker reports do. Levels without records are omitted, and
+// nothing is logged for intervals without records. Reports are not counted
+// themselves.
+//
+// Call the returned func to stop reporting.
+func (c *LevelCounter) Report(l *Logger, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var prev [numLevels]int64
+		for {
+			select {
+			case <-ticker.C:
+				c.report(l, &prev)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (c *LevelCounter) report(l *Logger, prev *[numLevels]int64) {
+	var fields []zapcore.Field
+	for i := range prev {
+		total := atomic.LoadInt64(&c.total[i])
+		if n := total - prev[i]; n > 0 {
+			fields = append(fields, zap.Int64("sql.levels."+(zapcore.DebugLevel+zapcore.Level(i)).String(), n))
+		}
+		prev[i] = total
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	l = l.load()
+	l.log(Record{
+		Message: "gormzap: log levels",
+		Level:   l.level,
+		Fields:  fields,
+		stats:   true,
+	})
+}
+
+// Recent returns the number of records written with the level within the
+// last d, with one second precision. d is capped to one minute.
+func (c *LevelCounter) Recent(level zapcore.Level, d time.Duration) int64 {
+	i := int(level - zapcore.DebugLevel)
+	if i < 0 || i >= numLevels {
+		return 0
+	}
+
+	secs := int64((d + time.Second - 1) / time.Second)
+	if secs > levelCounterWindow {
+		secs = levelCounterWindow
+	}
+	now := time.Now().Unix()
+	from := now - secs
+
+	var n int64
+	for j := range c.buckets {
+		b := &c.buckets[j]
+		if sec := atomic.LoadInt64(&b.sec); sec > from && sec <= now {
+			n += atomic.LoadInt64(&b.counts[i])
+		}
+	}
+	return n
+}
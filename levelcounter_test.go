@@ -0,0 +1,72 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithLevelCounter(t *testing.T) {
+	c := gormzap.NewLevelCounter()
+	l, _ := logger(gormzap.WithLevelCounter(c), gormzap.WithLevel(zapcore.InfoLevel))
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+	l.Print("/some/file.go:36", errors.New("some serious error!"))
+
+	if n := c.Count(zapcore.InfoLevel); n != 1 {
+		t.Fatalf("Expected 1 info record but got %d", n)
+	}
+	if n := c.Count(zapcore.ErrorLevel); n != 2 {
+		t.Fatalf("Expected 2 error records but got %d", n)
+	}
+	if n := c.Recent(zapcore.ErrorLevel, time.Minute); n != 2 {
+		t.Fatalf("Expected 2 recent error records but got %d", n)
+	}
+	if n := c.Count(zapcore.WarnLevel); n != 0 {
+		t.Fatalf("Expected no warn records but got %d", n)
+	}
+}
+
+func TestWithLevelCounter_cloneWith(t *testing.T) {
+	c := gormzap.NewLevelCounter()
+	l, _ := logger(gormzap.WithLevelCounter(c))
+	l = l.CloneWith(gormzap.WithLevelCounter(c))
+
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+
+	if n := c.Count(zapcore.ErrorLevel); n != 1 {
+		t.Fatalf("Expected 1 error record but got %d", n)
+	}
+}
+
+func TestLevelCounter_Report(t *testing.T) {
+	c := gormzap.NewLevelCounter()
+	records := &recordSink{}
+	l, _ := logger(gormzap.WithLevelCounter(c), gormzap.WithSinks(records))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+	l.Print("/some/file.go:36", errors.New("some serious error!"))
+
+	stop := c.Report(l, time.Millisecond*10)
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for records.FilterMessage("gormzap: log levels").Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+
+	reports := records.FilterMessage("gormzap: log levels").AllRecords()
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report but got %d", len(reports))
+	}
+	if f := reports[0].Fields; len(f) != 1 || f[0].Key != "sql.levels.error" || f[0].Integer != 2 {
+		t.Fatalf("Unexpected report fields %v", f)
+	}
+	if n := c.Count(zapcore.DebugLevel); n != 0 {
+		t.Fatalf("Expected report not to be counted but got %d debug records", n)
+	}
+}
@@ -0,0 +1,80 @@
+package gormzap
+
+import (
+	"strings"
+)
+
+// tokenKind is a kind of SQL statement chunk produced by lexSQL.
+type tokenKind int
+
+const (
+	// tokenText is any part of the statement outside of literals and comments.
+	tokenText tokenKind = iota
+	// tokenString is a single-quoted string literal, including quotes.
+	tokenString
+	// tokenIdent is a double-quoted or backquoted identifier, including quotes.
+	tokenIdent
+	// tokenComment is a line (--) or block (/* */) comment, including markers.
+	tokenComment
+)
+
+// lexSQL splits SQL statement into chunks of text, literals and comments and
+// calls fn for each of them in order. Concatenation of all chunks is equal to
+// the statement. Unterminated literals and comments last till the end.
+func lexSQL(sql string, fn func(kind tokenKind, tok string)) {
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			fn(tokenText, sql[start:end])
+		}
+	}
+
+	for i := 0; i < len(sql); {
+		var kind tokenKind
+		var end int
+
+		switch c := sql[i]; {
+		case c == '\'':
+			kind, end = tokenString, quoteEnd(sql, i, '\'')
+		case c == '"' || c == '`':
+			kind, end = tokenIdent, quoteEnd(sql, i, c)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			kind, end = tokenComment, len(sql)
+			if n := strings.IndexByte(sql[i:], '\n'); n != -1 {
+				end = i + n
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			kind, end = tokenComment, len(sql)
+			if n := strings.Index(sql[i+2:], "*/"); n != -1 {
+				end = i + 2 + n + 2
+			}
+		default:
+			i++
+			continue
+		}
+
+		flush(i)
+		fn(kind, sql[i:end])
+		i, start = end, end
+	}
+
+	flush(len(sql))
+}
+
+// quoteEnd returns index after the closing quote q for a literal starting at
+// i. Doubled quotes and backslash escapes are considered part of the literal.
+func quoteEnd(sql string, i int, q byte) int {
+	for j := i + 1; j < len(sql); j++ {
+		switch sql[j] {
+		case '\\':
+			j++
+		case q:
+			if j+1 < len(sql) && sql[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(sql)
+}
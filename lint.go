@@ -0,0 +1,203 @@
+package gormzap
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Rule inspects SQL query records and reports findings about them.
+// Rules are checked only for records that contain SQL queries.
+type Rule interface {
+	Check(r Record) []Finding
+}
+
+// RuleFunc is an adapter to allow the use of ordinary functions as rules.
+type RuleFunc func(r Record) []Finding
+
+// Check implements Rule.
+func (f RuleFunc) Check(r Record) []Finding {
+	return f(r)
+}
+
+// Finding is a result of a Rule check.
+type Finding struct {
+	// Lint is a name of the violated lint rule, e.g. "select_star".
+	// If not empty, it is appended to Record.Lint.
+	Lint string
+
+	// Fields are additional fields attached to the record.
+	Fields []zapcore.Field
+
+	// Escalate shows if the record should be logged with at least Level.
+	Escalate bool
+	Level    zapcore.Level
+}
+
+// WithRules returns Logger option that adds rules checking every SQL query
+// record. Findings of the rules are applied to the record in order.
+func WithRules(rules ...Rule) LoggerOption {
+	return func(l *Logger) {
+		l.rules = append(l.rules, rules...)
+	}
+}
+
+// WithSelectStarLint returns Logger option that tags queries selecting all
+// columns with `*` with "select_star" lint, and logs them with the given level
+// unless the record already has a higher one.
+//
+// This is a shortcut for WithRules(SelectStarRule(level)).
+func WithSelectStarLint(level zapcore.Level) LoggerOption {
+	return WithRules(SelectStarRule(level))
+}
+
+// Built-in lint names.
+const (
+	LintSelectStar      = "select_star"
+	LintUnboundedWrite  = "unbounded_write"
+	LintLeadingWildcard = "leading_wildcard_like"
+)
+
+// SelectStarRule returns a Rule that reports queries selecting all columns
+// with `*`, escalating them to the given level.
+func SelectStarRule(level zapcore.Level) Rule {
+	return lintRule(LintSelectStar, level, isSelectStar)
+}
+
+// UnboundedWriteRule returns a Rule that reports UPDATE and DELETE
+// statements without WHERE clause, escalating them to the given level.
+func UnboundedWriteRule(level zapcore.Level) Rule {
+	return lintRule(LintUnboundedWrite, level, isUnboundedWrite)
+}
+
+// LeadingWildcardLikeRule returns a Rule that reports LIKE and ILIKE
+// predicates with a pattern starting with `%`, which prevents the database from
+// using an index, escalating them to the given level.
+//
+// Patterns are recognized both as literals in the statement and as bind values
+// of its placeholders, so the rule works with WithoutValues too.
+func LeadingWildcardLikeRule(level zapcore.Level) Rule {
+	return lintRule(LintLeadingWildcard, level, isLeadingWildcardLike)
+}
+
+func lintRule(lint string, level zapcore.Level, match func(r Record) bool) Rule {
+	return RuleFunc(func(r Record) []Finding {
+		if !match(r) {
+			return nil
+		}
+		return []Finding{{Lint: lint, Escalate: true, Level: level}}
+	})
+}
+
+func (l *Logger) applyRules(rec *Record) {
+	for _, rule := range l.rules {
+		for _, f := range rule.Check(*rec) {
+			if f.Lint != "" {
+				rec.Lint = append(rec.Lint, f.Lint)
+			}
+			rec.Fields = append(rec.Fields, f.Fields...)
+			if f.Escalate {
+				escalate(rec, f.Level)
+			}
+		}
+	}
+}
+
+var selectStarRegexp = regexp.MustCompile(`(?i)(\bselect\s+(distinct\s+)?|,\s*)(\w+\.)?\*`)
+
+func isSelectStar(r Record) bool {
+	return r.Operation() == "SELECT" && selectStarRegexp.MatchString(maskSQL(r.statement()))
+}
+
+var whereRegexp = regexp.MustCompile(`(?i)\bwhere\b`)
+
+func isUnboundedWrite(r Record) bool {
+	switch r.Operation() {
+	case "UPDATE", "DELETE":
+		hasWhere := false
+		lexSQL(r.statement(), func(kind tokenKind, tok string) {
+			if kind == tokenText && whereRegexp.MatchString(tok) {
+				hasWhere = true
+			}
+		})
+		return !hasWhere
+	}
+	return false
+}
+
+var leadingWildcardLikeRegexp = regexp.MustCompile(`(?i)\bi?like\s+('%|\$(\d+)|\?|[@:](\w+))`)
+
+func isLeadingWildcardLike(r Record) bool {
+	stmt := maskSQL(r.statement())
+	for _, m := range leadingWildcardLikeRegexp.FindAllStringSubmatchIndex(stmt, -1) {
+		switch {
+		case stmt[m[2]] == '\'':
+			return true
+		case m[4] != -1:
+			n, _ := strconv.Atoi(stmt[m[4]:m[5]])
+			if n > 0 && n <= len(r.Args) && isLeadingWildcard(r.Args[n-1]) {
+				return true
+			}
+		case m[6] != -1:
+			name := stmt[m[6]:m[7]]
+			for _, v := range r.Args {
+				if na, ok := v.(sql.NamedArg); ok && na.Name == name && isLeadingWildcard(na.Value) {
+					return true
+				}
+			}
+		default:
+			// Positional "?" placeholders are numbered by their order, and
+			// doubled "??" ones are masked.
+			n := strings.Count(stmt[:m[2]], "?")
+			if n < len(r.Args) && isLeadingWildcard(r.Args[n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maskSQL returns sql with comments, quoted identifiers and doubled "??"
+// placeholders masked, and string literals other than those starting with
+// `%` emptied, so that lint rules match keywords, operators and placeholders
+// only in the statement itself.
+func maskSQL(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		switch kind {
+		case tokenText:
+			b.WriteString(strings.Replace(tok, "??", "__", -1))
+		case tokenString:
+			if strings.HasPrefix(tok, "'%") {
+				b.WriteString("'%'")
+			} else {
+				b.WriteString("''")
+			}
+		case tokenIdent:
+			b.WriteString("_")
+		case tokenComment:
+			b.WriteString(" ")
+		}
+	})
+	return b.String()
+}
+
+// isLeadingWildcard reports whether bind value v is a LIKE pattern starting
+// with `%`.
+func isLeadingWildcard(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.HasPrefix(v, "%")
+	case []byte:
+		return len(v) > 0 && v[0] == '%'
+	case *string:
+		return v != nil && strings.HasPrefix(*v, "%")
+	case sql.NamedArg:
+		return isLeadingWildcard(v.Value)
+	}
+	return false
+}
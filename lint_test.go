@@ -0,0 +1,247 @@
+package gormzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithSelectStarLint(t *testing.T) {
+	testCases := []struct {
+		sql      string
+		expected string
+	}{
+		{
+			sql:      "SELECT * FROM test",
+			expected: `{"level":"info","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test","sql.rows_affected":1,"sql.lint":"select_star"}`,
+		},
+		{
+			sql:      "SELECT DISTINCT t.* FROM test t",
+			expected: `{"level":"info","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT DISTINCT t.* FROM test t","sql.rows_affected":1,"sql.lint":"select_star"}`,
+		},
+		{
+			sql:      "SELECT count(*) FROM test",
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT count(*) FROM test","sql.rows_affected":1}`,
+		},
+		{
+			sql:      "SELECT id, price * 2 FROM test",
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id, price * 2 FROM test","sql.rows_affected":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			l, buf := logger(gormzap.WithSelectStarLint(zap.InfoLevel))
+
+			l.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				tc.sql,
+				[]interface{}{},
+				int64(1),
+			)
+
+			actual := buf.Lines()[0]
+			if actual != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithSelectStarLint_literals(t *testing.T) {
+	testCases := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{sql: "SELECT id FROM test WHERE name = $1", args: []interface{}{"a, *"}},
+		{sql: "SELECT id, ', *' FROM test"},
+		{sql: "SELECT id /* , * */ FROM test"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			l, records := observer(gormzap.WithSelectStarLint(zap.InfoLevel))
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, tc.sql, tc.args, int64(1))
+
+			if lint := records.AllRecords()[0].Lint; len(lint) != 0 {
+				t.Fatalf("Expected no lint but got %v", lint)
+			}
+		})
+	}
+}
+
+func TestWithRules(t *testing.T) {
+	t.Run("unbounded write", func(t *testing.T) {
+		l, buf := logger(gormzap.WithRules(gormzap.UnboundedWriteRule(zap.WarnLevel)))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"DELETE FROM test",
+			[]interface{}{},
+			int64(10),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"DELETE FROM test","sql.rows_affected":10,"sql.lint":"unbounded_write"}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("unbounded write with where in literals", func(t *testing.T) {
+		testCases := []struct {
+			sql      string
+			args     []interface{}
+			expected bool
+		}{
+			{sql: "UPDATE test SET note = $1", args: []interface{}{"see where"}, expected: true},
+			{sql: "UPDATE test SET note = 'see where'", expected: true},
+			{sql: "DELETE FROM test -- where", expected: true},
+			{sql: `DELETE FROM "where"`, expected: true},
+			{sql: "DELETE FROM test WHERE id = 1", expected: false},
+		}
+
+		for _, tc := range testCases {
+			l, records := observer(gormzap.WithRules(gormzap.UnboundedWriteRule(zap.WarnLevel)))
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, tc.sql, tc.args, int64(1))
+
+			lint := records.AllRecords()[0].Lint
+			if actual := len(lint) == 1 && lint[0] == gormzap.LintUnboundedWrite; actual != tc.expected {
+				t.Fatalf("%s: expected lint %v but got %v", tc.sql, tc.expected, lint)
+			}
+		}
+	})
+
+	t.Run("user-defined rule", func(t *testing.T) {
+		rule := gormzap.RuleFunc(func(r gormzap.Record) []gormzap.Finding {
+			if r.RowsAffected == 0 {
+				return []gormzap.Finding{{Fields: []zapcore.Field{zap.Bool("sql.empty", true)}}}
+			}
+			return nil
+		})
+		l, buf := logger(gormzap.WithRules(rule, gormzap.SelectStarRule(zap.InfoLevel)))
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM test",
+			[]interface{}{},
+			int64(0),
+		)
+		expected := `{"level":"info","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test","sql.rows_affected":0,"sql.lint":"select_star","sql.empty":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func TestLeadingWildcardLikeRule(t *testing.T) {
+	testCases := []struct {
+		sql      string
+		args     []interface{}
+		expected string
+	}{
+		{
+			sql:      "SELECT id FROM test WHERE name LIKE $1",
+			args:     []interface{}{"%foo%"},
+			expected: `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id FROM test WHERE name LIKE '%foo%'","sql.rows_affected":1,"sql.lint":"leading_wildcard_like"}`,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE name ilike '%foo'",
+			args:     []interface{}{},
+			expected: `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id FROM test WHERE name ilike '%foo'","sql.rows_affected":1,"sql.lint":"leading_wildcard_like"}`,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE name LIKE $1",
+			args:     []interface{}{"foo%"},
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id FROM test WHERE name LIKE 'foo%'","sql.rows_affected":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			l, buf := logger(gormzap.WithRules(gormzap.LeadingWildcardLikeRule(zap.WarnLevel)))
+
+			l.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				tc.sql,
+				tc.args,
+				int64(1),
+			)
+
+			actual := buf.Lines()[0]
+			if actual != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLeadingWildcardLikeRule_withoutValues(t *testing.T) {
+	testCases := []struct {
+		sql      string
+		args     []interface{}
+		expected bool
+	}{
+		{
+			sql:      "SELECT id FROM test WHERE name LIKE $2 AND id = $1",
+			args:     []interface{}{42, "%foo"},
+			expected: true,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE id = ? AND name LIKE ?",
+			args:     []interface{}{42, []byte("%foo")},
+			expected: true,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE name LIKE ? AND id = ?",
+			args:     []interface{}{"foo%", "%42"},
+			expected: false,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE note = '?' /* ? */ AND name LIKE ?",
+			args:     []interface{}{"%foo"},
+			expected: true,
+		},
+		{
+			sql:      "SELECT ?? FROM test WHERE name LIKE ?",
+			args:     []interface{}{"%foo"},
+			expected: true,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE note = 'name LIKE ?' AND name = ?",
+			args:     []interface{}{"%foo"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			l, records := observer(
+				gormzap.WithoutValues(),
+				gormzap.WithRules(gormzap.LeadingWildcardLikeRule(zap.WarnLevel)),
+			)
+
+			l.Print("sql", "/some/file.go:34", time.Millisecond*5, tc.sql, tc.args, int64(1))
+
+			rec := records.AllRecords()[0]
+			if actual := len(rec.Lint) == 1 && rec.Lint[0] == gormzap.LintLeadingWildcard; actual != tc.expected {
+				t.Fatalf("Expected lint %v but got %v", tc.expected, rec.Lint)
+			}
+		})
+	}
+}
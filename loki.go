@@ -0,0 +1,60 @@
+package gormzap
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LokiRecordToFields is an encoder func for gormzap log records that emits
+// only low-cardinality fields suitable for Loki labels: "sql.operation",
+// "sql.table", "sql.slow" and "sql.lint" for queries, and "sql.cancelled" for
+// errors. Additional fields of the record, e.g. those attached by rules or
+// options, are logged as well. Use it with NewLoki, which also moves the SQL
+// query and other high-cardinality data into the log message.
+func LokiRecordToFields(r Record) []zapcore.Field {
+	if r.SQL == "" {
+		var fields []zapcore.Field
+		if r.Cancelled {
+			fields = append(fields, zap.Bool("sql.cancelled", true))
+		}
+		return append(fields, r.Fields...)
+	}
+
+	fields := []zapcore.Field{zap.String("sql.operation", r.Operation())}
+	if table := r.Table(); table != "" {
+		fields = append(fields, zap.String("sql.table", table))
+	}
+	fields = append(fields, zap.Bool("sql.slow", r.Slow))
+	if len(r.Lint) > 0 {
+		fields = append(fields, zap.String("sql.lint", strings.Join(r.Lint, ",")))
+	}
+	return append(fields, r.Fields...)
+}
+
+// lokiMessage returns log message with high-cardinality data of the record,
+// e.g.
+//  SELECT * FROM users WHERE id = 1 [5ms] [rows:1] /app/users.go:42
+func lokiMessage(r Record) string {
+	if r.SQL == "" {
+		if r.Source == "" {
+			return r.Message
+		}
+		return r.Message + " " + r.Source
+	}
+
+	buf := make([]byte, 0, len(r.SQL)+len(r.Source)+32)
+	buf = append(buf, r.SQL...)
+	buf = append(buf, " ["...)
+	buf = append(buf, r.Duration.String()...)
+	buf = append(buf, "] [rows:"...)
+	buf = strconv.AppendInt(buf, r.RowsAffected, 10)
+	buf = append(buf, ']')
+	if r.Source != "" {
+		buf = append(buf, ' ')
+		buf = append(buf, r.Source...)
+	}
+	return string(buf)
+}
@@ -0,0 +1,84 @@
+package gormzap
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Migration is a gorm logger that groups statements of a database migration
+// under a single migration ID, and logs a summary when the migration ends.
+//
+// Example usage:
+//  m := log.StartMigration("20180521-add-users")
+//  orm.SetLogger(m)
+//  orm.AutoMigrate(&User{})
+//  m.End()
+//  orm.SetLogger(log)
+type Migration struct {
+	logger *Logger
+	id     string
+	start  time.Time
+
+	statements int64
+	errors     int64
+	duration   int64
+}
+
+// StartMigration logs the start of a migration with the given ID and returns
+// a logger for its statements. Migration's records are logged the same way
+// as Logger's, but with additional "sql.migration_id" field.
+func (l *Logger) StartMigration(id string) *Migration {
+	m := &Migration{
+		logger: l,
+		id:     id,
+		start:  time.Now(),
+	}
+
+	l = l.load()
+	l.log(Record{
+		Message:     "gorm migration started",
+		Level:       l.level,
+		MigrationID: id,
+	})
+
+	return m
+}
+
+// Print implements gorm's logger interface.
+func (m *Migration) Print(values ...interface{}) {
+	l := m.logger.load()
+
+	rec := l.newRecord(values...)
+	rec.MigrationID = m.id
+
+	if rec.SQL != "" {
+		atomic.AddInt64(&m.statements, 1)
+		atomic.AddInt64(&m.duration, int64(rec.Duration))
+	}
+	if rec.Level >= zapcore.ErrorLevel {
+		atomic.AddInt64(&m.errors, 1)
+	}
+
+	l.log(rec)
+}
+
+// End logs the migration summary: elapsed time, number of executed statements
+// and errors, and total time spent in the database.
+func (m *Migration) End() {
+	l := m.logger.load()
+
+	l.log(Record{
+		Message:     "gorm migration finished",
+		Level:       l.level,
+		MigrationID: m.id,
+		Fields: []zapcore.Field{
+			zap.Duration("sql.migration.elapsed", time.Since(m.start)),
+			zap.Duration("sql.migration.duration", time.Duration(atomic.LoadInt64(&m.duration))),
+			zap.Int64("sql.migration.statements", atomic.LoadInt64(&m.statements)),
+			zap.Int64("sql.migration.errors", atomic.LoadInt64(&m.errors)),
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package gormzap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigration(t *testing.T) {
+	l, buf := logger()
+
+	m := l.StartMigration("init")
+	m.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"CREATE TABLE test (id int)",
+		[]interface{}{},
+		int64(0),
+	)
+	m.Print(
+		"sql",
+		"/some/file.go:35",
+		time.Millisecond*10,
+		"CREATE INDEX test_id ON test (id)",
+		[]interface{}{},
+		int64(0),
+	)
+	m.Print("/some/file.go:36", errors.New("some serious error!"))
+	m.End()
+
+	lines := buf.Lines()
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines but got %d", len(lines))
+	}
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm migration started","sql.source":"","sql.migration_id":"init"}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"CREATE TABLE test (id int)","sql.rows_affected":0,"sql.migration_id":"init"}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:35","sql.duration":"10ms","sql.query":"CREATE INDEX test_id ON test (id)","sql.rows_affected":0,"sql.migration_id":"init"}`,
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:36","sql.migration_id":"init"}`,
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+
+	summary := lines[4]
+	for _, e := range []string{
+		`"msg":"gorm migration finished"`,
+		`"sql.migration_id":"init"`,
+		`"sql.migration.duration":"15ms"`,
+		`"sql.migration.statements":2`,
+		`"sql.migration.errors":1`,
+	} {
+		if !strings.Contains(summary, e) {
+			t.Fatalf("Expected %s to contain %s", summary, e)
+		}
+	}
+}
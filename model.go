@@ -0,0 +1,65 @@
+package gormzap
+
+// ModelInfo describes the gorm model of the operation a query is made by,
+// which is not passed to gorm logger. It is collected by gorm callbacks of
+// gormzapplugin package.
+type ModelInfo struct {
+	// Model is the model struct name, e.g. "User".
+	Model string
+	// Schema is the table name of the model, e.g. "users".
+	Schema string
+	// PrimaryKey is the primary key value of the model, if it is set.
+	PrimaryKey string
+	// Callback is the gorm callback chain of the operation: "create",
+	// "query", "update", "delete" or "row_query".
+	Callback string
+}
+
+// ModelLogger is a gorm logger that adds model info to its records. Records
+// are logged the same way as Logger's, but with additional "sql.model",
+// "sql.schema", "sql.primary_key" and "sql.callback" fields.
+type ModelLogger struct {
+	logger    *Logger
+	info      ModelInfo
+	statement StatementFunc
+}
+
+// StatementFunc returns the SQL statement and bind values of the operation,
+// as far as they are built at the moment of call.
+type StatementFunc func() (statement string, args []interface{})
+
+// WithModelInfo returns a logger adding info to its records.
+func (l *Logger) WithModelInfo(info ModelInfo) *ModelLogger {
+	return &ModelLogger{
+		logger: l,
+		info:   info,
+	}
+}
+
+// WithStatementFunc returns a logger that correlates error records with the
+// failing query: gorm logs SQL errors as separate records without the query,
+// so the logger sets Statement and Args of such records from f, and logs the
+// statement as "sql.statement" field.
+func (m *ModelLogger) WithStatementFunc(f StatementFunc) *ModelLogger {
+	return &ModelLogger{
+		logger:    m.logger,
+		info:      m.info,
+		statement: f,
+	}
+}
+
+// Print implements gorm's logger interface.
+func (m *ModelLogger) Print(values ...interface{}) {
+	l := m.logger.load()
+
+	rec := l.newRecord(values...)
+	rec.Model = m.info.Model
+	rec.Schema = m.info.Schema
+	rec.PrimaryKey = m.info.PrimaryKey
+	rec.Callback = m.info.Callback
+	if m.statement != nil && rec.SQL == "" && rec.Err != nil {
+		rec.Statement, rec.Args = m.statement()
+	}
+
+	l.log(rec)
+}
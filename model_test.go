@@ -0,0 +1,47 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestLogger_WithModelInfo(t *testing.T) {
+	l, buf := logger()
+
+	ml := l.WithModelInfo(gormzap.ModelInfo{
+		Model:      "User",
+		Schema:     "users",
+		PrimaryKey: "1",
+		Callback:   "update",
+	})
+	ml.Print("sql", "/some/file.go:34", time.Millisecond*5, "UPDATE users SET name = 'foo' WHERE id = 1", []interface{}{}, int64(1))
+
+	expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"UPDATE users SET name = 'foo' WHERE id = 1","sql.rows_affected":1,"sql.model":"User","sql.schema":"users","sql.primary_key":"1","sql.callback":"update"}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestModelLogger_WithStatementFunc(t *testing.T) {
+	l, buf := logger()
+
+	ml := l.WithModelInfo(gormzap.ModelInfo{Model: "User"}).WithStatementFunc(func() (string, []interface{}) {
+		return "DELETE FROM users WHERE id = $1", []interface{}{1}
+	})
+	ml.Print("log", "/some/file.go:34", errors.New("some serious error!"))
+	ml.Print("log", "/some/file.go:35", "some message")
+
+	expected := []string{
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:34","sql.statement":"DELETE FROM users WHERE id = $1"}`,
+		`{"level":"debug","msg":"some message","sql.source":"/some/file.go:35"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package gormzap
+
+// Parser parses SQL statements. By default, gormzap extracts operation and
+// tables of queries, and normalizes them for hashing, with regular
+// expressions, which may be inaccurate for complex statements. Parser is an
+// integration point for a real SQL parser, e.g. vitess sqlparser, see
+// gormzapvitess package, or pg_query, for users willing to take the
+// dependency.
+type Parser interface {
+	Parse(sql string) (*ParsedStatement, error)
+}
+
+// ParserFunc is an adapter to allow the use of ordinary functions as parsers.
+type ParserFunc func(sql string) (*ParsedStatement, error)
+
+// Parse implements Parser.
+func (f ParserFunc) Parse(sql string) (*ParsedStatement, error) {
+	return f(sql)
+}
+
+// ParsedStatement is a result of parsing SQL statement. Empty fields are
+// filled by the built-in regular expression based inspection.
+type ParsedStatement struct {
+	// Operation is the uppercased keyword of the statement, e.g. "SELECT".
+	Operation string
+	// Tables are names of tables the statement refers to, without quotes,
+	// in order of appearance.
+	Tables []string
+	// Normalized is the statement in a canonical form, with literals and
+	// placeholders replaced, used for query hash and error fingerprint.
+	Normalized string
+	// Joins is the number of joins of the statement. It is not filled by the
+	// built-in inspection, so it is zero unless the parser counts joins.
+	Joins int
+}
+
+// WithParser returns Logger option that parses statements of SQL query
+// records with p, and sets Record.Parsed. Parsed statement is used by
+// Record.Operation and Record.Table, lint rules, query hash, error tags and
+// encoders relying on them. If p fails to parse a statement, the built-in
+// inspection is used for it.
+func WithParser(p Parser) LoggerOption {
+	return func(l *Logger) {
+		l.parser = p
+	}
+}
+
+// parse sets parsed statement of the record.
+func (l *Logger) parse(rec *Record) {
+	ps, err := l.parser.Parse(rec.Statement)
+	if err != nil {
+		return
+	}
+	rec.Parsed = ps
+}
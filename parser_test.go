@@ -0,0 +1,45 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithParser(t *testing.T) {
+	parser := gormzap.ParserFunc(func(sql string) (*gormzap.ParsedStatement, error) {
+		if sql == "invalid" {
+			return nil, errors.New("syntax error")
+		}
+		return &gormzap.ParsedStatement{
+			Operation:  "SELECT",
+			Tables:     []string{"accounts", "users"},
+			Normalized: "select * from accounts join users",
+		}, nil
+	})
+
+	l, records := observer(gormzap.WithParser(parser), gormzap.WithQueryHash())
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "WITH a AS (SELECT 1) SELECT * FROM accounts JOIN users ON true", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "with a as (select 2) select * from accounts join users on false", []interface{}{}, int64(1))
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "invalid", []interface{}{}, int64(1))
+
+	recs := records.AllRecords()
+	if op := recs[0].Operation(); op != "SELECT" {
+		t.Fatalf("Expected operation SELECT but got %s", op)
+	}
+	if table := recs[0].Table(); table != "accounts" {
+		t.Fatalf("Expected table accounts but got %s", table)
+	}
+	if recs[0].QueryHash != recs[1].QueryHash {
+		t.Fatalf("Expected equal query hashes but got %s and %s", recs[0].QueryHash, recs[1].QueryHash)
+	}
+	if recs[2].Parsed != nil {
+		t.Fatalf("Expected no parsed statement but got %v", recs[2].Parsed)
+	}
+	if op := recs[2].Operation(); op != "INVALID" {
+		t.Fatalf("Expected fallback operation INVALID but got %s", op)
+	}
+}
@@ -0,0 +1,47 @@
+package gormzap
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultSlowThreshold is the slow query threshold used by NewProduction.
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// NewProduction returns a new gorm logger with opinionated defaults for
+// production: queries are logged with info level, queries taking longer than
+// DefaultSlowThreshold are logged with warn level, and bind values are not
+// logged. Additional options are applied after the defaults.
+func NewProduction(origin *zap.Logger, opts ...LoggerOption) *Logger {
+	return New(origin, append([]LoggerOption{
+		WithLevel(zap.InfoLevel),
+		WithSlowThreshold(DefaultSlowThreshold),
+		WithoutValues(),
+	}, opts...)...)
+}
+
+// NewDevelopment returns a new gorm logger with opinionated defaults for
+// development: queries are logged with debug level, on several lines and
+// with highlighted keywords. Additional options are applied after the
+// defaults.
+func NewDevelopment(origin *zap.Logger, opts ...LoggerOption) *Logger {
+	return New(origin, append([]LoggerOption{
+		WithLevel(zap.DebugLevel),
+		WithPrettySQL(),
+		WithColors(),
+	}, opts...)...)
+}
+
+// NewLoki returns a new gorm logger with settings designed for Loki: the SQL
+// query, its duration, rows affected and source are logged in the message,
+// and only low-cardinality fields suitable for labels are logged with
+// LokiRecordToFields. Additional options are applied after the defaults.
+func NewLoki(origin *zap.Logger, opts ...LoggerOption) *Logger {
+	return New(origin, append([]LoggerOption{
+		WithRecordToFields(LokiRecordToFields),
+		func(l *Logger) {
+			l.messageFunc = lokiMessage
+		},
+	}, opts...)...)
+}
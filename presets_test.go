@@ -0,0 +1,129 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewProduction(t *testing.T) {
+	t.Run("fast query", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewProduction(z)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM test WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"info","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT * FROM test WHERE id = $1","sql.rows_affected":1}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("slow query", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewProduction(z)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Second,
+			"SELECT * FROM test WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"warn","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"1s","sql.query":"SELECT * FROM test WHERE id = $1","sql.rows_affected":1,"sql.slow":true}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+}
+
+func TestNewDevelopment(t *testing.T) {
+	z, buf := zapLogger()
+	l := gormzap.NewDevelopment(z, gormzap.WithRecordToFields(func(r gormzap.Record) []zapcore.Field {
+		return []zapcore.Field{zap.String("sql", r.SQL)}
+	}))
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT id FROM test WHERE name = $1 ORDER BY id",
+		[]interface{}{"from"},
+		int64(1),
+	)
+	expected := `{"level":"debug","msg":"gorm query","sql":"\u001b[35mSELECT\u001b[0m id\n\u001b[35mFROM\u001b[0m test\n\u001b[35mWHERE\u001b[0m name = \u001b[32m'from'\u001b[0m\n\u001b[35mORDER\u001b[0m \u001b[35mBY\u001b[0m id"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestNewLoki(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewLoki(z)
+
+		l.Print(
+			"sql",
+			"/some/file.go:34",
+			time.Millisecond*5,
+			"SELECT * FROM \"users\" WHERE id = $1",
+			[]interface{}{42},
+			int64(1),
+		)
+		expected := `{"level":"debug","msg":"SELECT * FROM \"users\" WHERE id = 42 [5ms] [rows:1] /some/file.go:34","sql.operation":"SELECT","sql.table":"users","sql.slow":false}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewLoki(z)
+
+		l.Print("/some/file.go:32", errors.New("some serious error!"))
+		expected := `{"level":"error","msg":"some serious error! /some/file.go:32"}`
+
+		actual := buf.Lines()[0]
+		if actual != expected {
+			t.Fatalf("Expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("record fields", func(t *testing.T) {
+		z, buf := zapLogger()
+		l := gormzap.NewLoki(z, gormzap.WithErrorTags(), gormzap.WithSelectStarLint(zap.WarnLevel), gormzap.WithCancelledLevel(zap.ErrorLevel))
+
+		l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT * FROM users", []interface{}{}, int64(1))
+		l.Print("/some/file.go:32", context.Canceled)
+
+		expected := []string{
+			`{"level":"warn","msg":"SELECT * FROM users [5ms] [rows:1] /some/file.go:34","sql.operation":"SELECT","sql.table":"users","sql.slow":false,"sql.lint":"select_star"}`,
+			`{"level":"error","msg":"context canceled /some/file.go:32","sql.cancelled":true,"error.type":"*errors.errorString","error.fingerprint":"6d8f307bfedf07a7"}`,
+		}
+		for i, e := range expected {
+			if actual := buf.Lines()[i]; actual != e {
+				t.Fatalf("Expected %s but got %s", e, actual)
+			}
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package gormzap
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// LogQuery logs a query made without gorm, e.g. by a hand-rolled repository
+// on top of database/sql, with the same records as gorm queries. statement
+// is the query with placeholders, args are its bind values, d is the time it
+// took and rows is the number of rows it affected or returned. If err is not
+// nil, it is logged first as an error record carrying the statement, as gorm
+// does for failed queries. The query is logged bound to ctx, see
+// WithContext, and the source is the caller of LogQuery. Queries are
+// numbered, and their database time is summed, only if ctx is created with
+// NewContext.
+//
+// Example usage:
+//  start := time.Now()
+//  res, err := db.ExecContext(ctx, query, args...)
+//  n, _ := res.RowsAffected()
+//  log.LogQuery(ctx, query, args, time.Since(start), n, err)
+func (l *Logger) LogQuery(ctx context.Context, statement string, args []interface{}, d time.Duration, rows int64, err error) {
+	var source string
+	if l.SourceEnabled() {
+		if _, file, line, ok := runtime.Caller(1); ok {
+			source = fmt.Sprintf("%s:%d", file, line)
+		}
+	}
+	l.LogQueryFrom(ctx, source, statement, args, d, rows, err)
+}
+
+// LogQueryFrom is like LogQuery, but logs the query with the given source
+// instead of the caller, e.g. for driver wrappers that look for the source
+// of a query outside of their own frames.
+func (l *Logger) LogQueryFrom(ctx context.Context, source, statement string, args []interface{}, d time.Duration, rows int64, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cur := l.load()
+	if cur.withoutSource {
+		source = ""
+	}
+
+	// Unlike WithContext, queries are not numbered unless ctx carries query
+	// statistics, because each call would number its query alone.
+	stats, _ := ctx.Value(contextStatsKey{}).(*contextStats)
+	c := &ContextLogger{logger: l, ctx: ctx, stats: stats}
+	if err != nil {
+		rec := c.newRecord(cur, source, err)
+		rec.Statement, rec.Args = statement, args
+		cur.log(rec)
+	}
+	cur.log(c.contextRecord(cur, cur.queryRecord(source, d, statement, args, rows)))
+}
@@ -0,0 +1,75 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogger_LogQuery(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		l, records := observer()
+		l.LogQuery(context.Background(), "SELECT * FROM users WHERE id = $1", []interface{}{1}, time.Millisecond*5, 1, nil)
+
+		recs := records.AllRecords()
+		if len(recs) != 1 {
+			t.Fatalf("Expected 1 record but got %d", len(recs))
+		}
+		rec := recs[0]
+		if rec.SQL != "SELECT * FROM users WHERE id = 1" {
+			t.Errorf("Unexpected SQL: %s", rec.SQL)
+		}
+		if rec.Duration != time.Millisecond*5 || rec.RowsAffected != 1 {
+			t.Errorf("Unexpected record: %+v", rec)
+		}
+		if !strings.Contains(rec.Source, "query_test.go:") {
+			t.Errorf("Expected source of the caller but got %s", rec.Source)
+		}
+	})
+
+	t.Run("context stats", func(t *testing.T) {
+		l, buf := logger()
+		l.LogQuery(context.Background(), "SELECT 1", nil, time.Millisecond*5, 1, nil)
+		l.LogQuery(context.Background(), "SELECT 2", nil, time.Millisecond*5, 1, nil)
+
+		for _, line := range buf.Lines() {
+			if strings.Contains(line, "sql.seq") || strings.Contains(line, "sql.db_time") {
+				t.Errorf("Expected no context stats without NewContext but got %s", line)
+			}
+		}
+
+		l, records := observer()
+		ctx := gormzap.NewContext(context.Background())
+		l.LogQuery(ctx, "SELECT 1", nil, time.Millisecond*5, 1, nil)
+		l.LogQuery(ctx, "SELECT 2", nil, time.Millisecond*5, 1, nil)
+
+		recs := records.AllRecords()
+		if recs[1].Seq != 2 || recs[1].DBTime != time.Millisecond*10 {
+			t.Errorf("Expected queries numbered within the context but got %+v", recs[1])
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		l, records := observer()
+		l.LogQuery(context.Background(), "DELETE FROM users WHERE id = $1", []interface{}{1}, time.Millisecond, 0, errors.New("permission denied"))
+
+		recs := records.AllRecords()
+		if len(recs) != 2 {
+			t.Fatalf("Expected 2 records but got %d", len(recs))
+		}
+		if recs[0].Level != zapcore.ErrorLevel || recs[0].Message != "permission denied" {
+			t.Errorf("Unexpected error record: %+v", recs[0])
+		}
+		if recs[0].Statement != "DELETE FROM users WHERE id = $1" {
+			t.Errorf("Unexpected error record statement: %s", recs[0].Statement)
+		}
+		if recs[1].SQL != "DELETE FROM users WHERE id = 1" {
+			t.Errorf("Unexpected SQL: %s", recs[1].SQL)
+		}
+	})
+}
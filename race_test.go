@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package gormzap_test
+
+// raceEnabled reports if the race detector is enabled, which changes
+// allocation counts.
+const raceEnabled = true
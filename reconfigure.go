@@ -0,0 +1,86 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// liveConfig holds logger configuration replaced with Reconfigure. It is
+// shared by the logger copies which gorm makes for every database handle.
+type liveConfig struct {
+	mu sync.Mutex
+	v  atomic.Value
+}
+
+// load returns the current logger configuration.
+func (l *Logger) load() *Logger {
+	if c, ok := l.live.v.Load().(*Logger); ok {
+		return c
+	}
+	return l
+}
+
+// Reconfigure replaces settings of the running logger covered by Config with
+// the ones from cfg. Settings not covered by Config, such as rules or encoder
+// func, are kept. It is safe to call Reconfigure concurrently with Print.
+func (l *Logger) Reconfigure(cfg Config) {
+	l.live.mu.Lock()
+	defer l.live.mu.Unlock()
+
+	c := l.load().clone()
+	cfg.apply(c)
+	c.prepare()
+	l.live.v.Store(c)
+}
+
+// DefaultWatchInterval is the interval WatchConfig uses if the given one is
+// not positive.
+const DefaultWatchInterval = time.Minute
+
+// WatchConfig calls load immediately and then every interval, and
+// reconfigures the logger whenever the returned config changes. Errors returned
+// by load are logged with warn level, keeping the current configuration.
+// It can be used to push settings from a config-management system, e.g.
+//  stop := log.WatchConfig(time.Minute, gormzap.ConfigFromEnv)
+//  defer stop()
+//
+// If interval is zero or negative, DefaultWatchInterval is used. Call the
+// returned func to stop watching.
+func (l *Logger) WatchConfig(interval time.Duration, load func() (Config, error)) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last Config
+		loaded := false
+		for {
+			cfg, err := load()
+			switch {
+			case err != nil:
+				l.load().origin.Warn("gormzap: failed to load config", zap.Error(err))
+			case !loaded || cfg != last:
+				l.Reconfigure(cfg)
+				last, loaded = cfg, true
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
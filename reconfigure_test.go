@@ -0,0 +1,98 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+)
+
+func TestLogger_Reconfigure(t *testing.T) {
+	l, buf := logger(gormzap.WithRules(gormzap.UnboundedWriteRule(zap.ErrorLevel)))
+
+	l.Reconfigure(gormzap.Config{
+		Level:       zap.InfoLevel,
+		Interpolate: true,
+		FieldPrefix: "db.",
+	})
+
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"UPDATE test SET name = $1",
+		[]interface{}{"foo"},
+		int64(1),
+	)
+	l.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Millisecond*5,
+		"SELECT * FROM test WHERE name = $1",
+		[]interface{}{"foo"},
+		int64(1),
+	)
+
+	expected := []string{
+		`{"level":"error","msg":"gorm query","db.source":"/some/file.go:34","db.duration":"5ms","db.query":"UPDATE test SET name = 'foo'","db.rows_affected":1,"db.lint":"unbounded_write"}`,
+		`{"level":"info","msg":"gorm query","db.source":"/some/file.go:34","db.duration":"5ms","db.query":"SELECT * FROM test WHERE name = 'foo'","db.rows_affected":1}`,
+	}
+	for i, e := range expected {
+		if actual := buf.Lines()[i]; actual != e {
+			t.Fatalf("Expected %s but got %s", e, actual)
+		}
+	}
+
+	if l.Level() != zap.InfoLevel {
+		t.Fatalf("Expected level %s but got %s", zap.InfoLevel, l.Level())
+	}
+}
+
+func TestLogger_WatchConfig(t *testing.T) {
+	l, buf := logger()
+
+	configs := make(chan gormzap.Config)
+	errs := make(chan error, 1)
+	errs <- errors.New("config unavailable")
+	calls := make(chan struct{}, 10)
+	stop := l.WatchConfig(time.Millisecond, func() (gormzap.Config, error) {
+		calls <- struct{}{}
+		select {
+		case err := <-errs:
+			return gormzap.Config{}, err
+		default:
+		}
+		return <-configs, nil
+	})
+	defer close(configs)
+	defer stop()
+
+	<-calls
+	<-calls
+	configs <- gormzap.Config{Level: zap.WarnLevel}
+	<-calls
+
+	if l.Level() != zap.WarnLevel {
+		t.Fatalf("Expected level %s but got %s", zap.WarnLevel, l.Level())
+	}
+
+	expected := `{"level":"warn","msg":"gormzap: failed to load config","error":"config unavailable"}`
+	if actual := buf.Lines()[0]; actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestLogger_WatchConfig_defaultInterval(t *testing.T) {
+	l, _ := logger()
+
+	calls := make(chan struct{}, 1)
+	stop := l.WatchConfig(0, func() (gormzap.Config, error) {
+		calls <- struct{}{}
+		return gormzap.Config{Level: zap.WarnLevel}, nil
+	})
+	defer stop()
+
+	<-calls
+}
@@ -1,6 +1,12 @@
 package gormzap
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -13,9 +19,176 @@ type Record struct {
 	Source  string
 	Level   zapcore.Level
 
+	// Err is the error logged by gorm, if any. Its message is the record
+	// Message.
+	Err error
+
+	// Cancelled shows if Err is caused by cancellation of the query context,
+	// e.g. context.Canceled. Such records are logged with a lower level if
+	// configured with WithCancelledLevel.
+	Cancelled bool
+
+	// Start and End are the times the query started and completed at, as
+	// estimated from the time it is logged at and its duration.
+	Start time.Time
+	End   time.Time
+
 	Duration     time.Duration
 	SQL          string
 	RowsAffected int64
+
+	// Statement is the SQL query as passed to the database, with placeholders
+	// instead of values, and Args are the bind values for it. Error records
+	// may have them set to the failing query, see ModelLogger.WithStatementFunc.
+	Statement string
+	Args      []interface{}
+
+	// Parsed is the statement parsed with the parser set by WithParser, or
+	// nil if it is not set or failed to parse the statement.
+	Parsed *ParsedStatement
+
+	// Slow shows if the query took longer than the configured threshold.
+	Slow bool
+
+	// DDL shows if the query is a DDL statement.
+	DDL bool
+
+	// Oversize shows if the query with interpolated values exceeded the size
+	// limit, so that SQL is the statement with placeholders, see
+	// WithMaxQueryBytes.
+	Oversize bool
+
+	// Plan holds the query execution plan, if it was requested.
+	Plan *QueryPlan
+
+	// QueryHash is a stable hash of the normalized query.
+	QueryHash string
+
+	// Tags holds tags parsed from the query comments.
+	Tags Tags
+
+	// Seq is a sequence number of the query within the context the logger is
+	// bound to, starting from 1, or zero if the logger is not bound to one.
+	Seq int64
+	// DBTime is total duration of the queries made within the context so
+	// far, including this one.
+	DBTime time.Duration
+	// BudgetExceeded shows if queries made within the context have exceeded
+	// its budget.
+	BudgetExceeded bool
+
+	// DeadlineRemaining is time remaining until the deadline of the context
+	// when the query completed, if HasDeadline is set.
+	DeadlineRemaining time.Duration
+	HasDeadline       bool
+
+	// TraceID and SpanID identify the trace the query is a part of, if they
+	// are extracted from the logger context with WithTraceIDs.
+	TraceID string
+	SpanID  string
+
+	// Contextless shows if the query is made without a request context, see
+	// WithContextlessTag.
+	Contextless bool
+
+	// Attempt is the number of the retry the query is made by, starting from
+	// 1, or zero if it is not a retry, and Backoff is the delay made before
+	// the retry. See NewContextWithRetry.
+	Attempt int
+	Backoff time.Duration
+
+	// Model, Schema, PrimaryKey and Callback describe the gorm model of the
+	// operation the query is made by, see ModelInfo.
+	Model      string
+	Schema     string
+	PrimaryKey string
+	Callback   string
+
+	// Role is the role of the database connection, set with WithRole.
+	Role string
+
+	// MigrationID is an ID of the migration the query is a part of.
+	MigrationID string
+
+	// Lint holds names of lint rules the SQL query has violated.
+	Lint []string
+
+	// Fields holds additional fields attached to the record, e.g. by rules.
+	Fields []zapcore.Field
+
+	// ctx is the context of ContextLogger the record is logged by.
+	ctx context.Context
+
+	// collapsedStatement is Statement with IN lists collapsed, see
+	// WithCollapsedInLists. It is logged instead of Statement if set.
+	collapsedStatement string
+
+	// stats marks records reporting stats of the logger itself, which are
+	// not counted by LevelCounter.
+	stats bool
+
+	// formatter is the value formatter of the logger the record is logged
+	// by, see Record.valueFormatter.
+	formatter *valueFormatter
+}
+
+// Operation returns the uppercased keyword of the SQL statement, e.g. "SELECT",
+// or empty string if the record is not a query.
+func (r Record) Operation() string {
+	if r.Parsed != nil && r.Parsed.Operation != "" {
+		return r.Parsed.Operation
+	}
+	return statementKeyword(r.statement())
+}
+
+// Table returns name of the first table the SQL statement refers to, or empty
+// string if it is not found.
+func (r Record) Table() string {
+	if r.Parsed != nil && len(r.Parsed.Tables) > 0 {
+		return r.Parsed.Tables[0]
+	}
+	return statementTable(r.statement())
+}
+
+// statement returns the statement of the record, or the logged query if the
+// statement is unknown.
+func (r Record) statement() string {
+	if r.collapsedStatement != "" {
+		return r.collapsedStatement
+	}
+	if r.Statement != "" {
+		return r.Statement
+	}
+	return r.SQL
+}
+
+// valueFormatter returns the value formatter of the logger the record is
+// logged by, or the default one if the record is not logged yet.
+func (r Record) valueFormatter() valueFormatter {
+	if r.formatter != nil {
+		return *r.formatter
+	}
+	return valueFormatter{maxLen: maxLen, maxBytes: maxQueryBytes}
+}
+
+// loggedStatement returns the statement of the record as it is logged, i.e.
+// with collapsed IN lists and truncated to the size limit of the logger, see
+// WithCollapsedInLists and WithMaxQueryBytes.
+func (r Record) loggedStatement() string {
+	stmt := r.Statement
+	if r.collapsedStatement != "" {
+		stmt = r.collapsedStatement
+	}
+	s, _ := r.valueFormatter().truncate(stmt)
+	return s
+}
+
+// normalized returns the normalized statement of the record.
+func (r Record) normalized() string {
+	if r.Parsed != nil && r.Parsed.Normalized != "" {
+		return r.Parsed.Normalized
+	}
+	return normalizeSQL(r.statement())
 }
 
 // RecordToFields func can encode gormzap Record into a slice of zap fields.
@@ -27,13 +200,238 @@ func DefaultRecordToFields(r Record) []zapcore.Field {
 	// by zap itself.
 
 	if r.SQL != "" {
-		return []zapcore.Field{
+		return appendQueryFields([]zapcore.Field{
+			zap.String("sql.source", r.Source),
+			zap.Duration("sql.duration", r.Duration),
+			zap.String("sql.query", r.SQL),
+			zap.Int64("sql.rows_affected", r.RowsAffected),
+		}, r)
+	}
+
+	return appendMessageFields([]zapcore.Field{zap.String("sql.source", r.Source)}, r)
+}
+
+// ParameterizedRecordToFields is an encoder func for gormzap log records that
+// logs SQL query as "sql.statement" with placeholders instead of values, and
+// bind values separately as "sql.args" array. This keeps statements of the
+// same shape identical in logs, which helps grouping them.
+func ParameterizedRecordToFields(r Record) []zapcore.Field {
+	if r.SQL != "" {
+		return appendQueryFields([]zapcore.Field{
+			zap.String("sql.source", r.Source),
+			zap.Duration("sql.duration", r.Duration),
+			zap.String("sql.statement", r.loggedStatement()),
+			zap.Array("sql.args", logArgs{r.Args, r.valueFormatter()}),
+			zap.Int("sql.args_count", len(r.Args)),
+			zap.Int64("sql.rows_affected", r.RowsAffected),
+		}, r)
+	}
+
+	return appendMessageFields([]zapcore.Field{zap.String("sql.source", r.Source)}, r)
+}
+
+// TemplateRecordToFields is an encoder func for gormzap log records that
+// logs both SQL query template with placeholders as "sql.template" and the
+// query with interpolated values as "sql.query", giving both the grouping
+// key and a concrete example in one record.
+func TemplateRecordToFields(r Record) []zapcore.Field {
+	if r.SQL != "" {
+		return appendQueryFields([]zapcore.Field{
 			zap.String("sql.source", r.Source),
 			zap.Duration("sql.duration", r.Duration),
+			zap.String("sql.template", r.loggedStatement()),
 			zap.String("sql.query", r.SQL),
 			zap.Int64("sql.rows_affected", r.RowsAffected),
+		}, r)
+	}
+
+	return appendMessageFields([]zapcore.Field{zap.String("sql.source", r.Source)}, r)
+}
+
+// appendQueryFields appends optional fields of SQL query record.
+func appendQueryFields(fields []zapcore.Field, r Record) []zapcore.Field {
+	if r.QueryHash != "" {
+		fields = append(fields, zap.String("sql.query_hash", r.QueryHash))
+	}
+	if r.MigrationID != "" {
+		fields = append(fields, zap.String("sql.migration_id", r.MigrationID))
+	}
+	fields = appendTraceFields(fields, r)
+	fields = appendRetryFields(fields, r)
+	if r.Model != "" {
+		fields = append(fields, zap.String("sql.model", r.Model))
+	}
+	if r.Schema != "" {
+		fields = append(fields, zap.String("sql.schema", r.Schema))
+	}
+	if r.PrimaryKey != "" {
+		fields = append(fields, zap.String("sql.primary_key", r.PrimaryKey))
+	}
+	if r.Callback != "" {
+		fields = append(fields, zap.String("sql.callback", r.Callback))
+	}
+	if r.Seq > 0 {
+		fields = append(fields, zap.Int64("sql.seq", r.Seq))
+		fields = append(fields, zap.Duration("sql.db_time", r.DBTime))
+	}
+	if r.HasDeadline {
+		fields = append(fields, zap.Duration("ctx.deadline_remaining", r.DeadlineRemaining))
+	}
+	if r.Contextless {
+		fields = append(fields, zap.Bool("sql.contextless", true))
+	}
+	if r.BudgetExceeded {
+		fields = append(fields, zap.Bool("sql.budget_exceeded", true))
+	}
+	if len(r.Tags) > 0 {
+		fields = append(fields, zap.Object("sql.tags", r.Tags))
+	}
+	if r.Slow {
+		fields = append(fields, zap.Bool("sql.slow", true))
+	}
+	if r.DDL {
+		fields = append(fields, zap.Bool("sql.ddl", true))
+	}
+	if r.Oversize {
+		fields = append(fields, zap.Bool("sql.oversize", true))
+	}
+	if r.Plan != nil {
+		fields = append(fields, zap.String("sql.plan", r.Plan.Text))
+		if r.Plan.Cost > 0 || r.Plan.Rows > 0 || r.Plan.SeqScan {
+			fields = append(fields,
+				zap.Float64("sql.plan.cost", r.Plan.Cost),
+				zap.Int64("sql.plan.rows", r.Plan.Rows),
+				zap.Bool("sql.plan.seq_scan", r.Plan.SeqScan),
+			)
+		}
+	}
+	if len(r.Lint) > 0 {
+		fields = append(fields, zap.String("sql.lint", strings.Join(r.Lint, ",")))
+	}
+	return append(fields, r.Fields...)
+}
+
+// appendMessageFields appends optional fields of message record.
+func appendMessageFields(fields []zapcore.Field, r Record) []zapcore.Field {
+	if r.Statement != "" {
+		fields = append(fields, zap.String("sql.statement", r.loggedStatement()))
+	}
+	if r.MigrationID != "" {
+		fields = append(fields, zap.String("sql.migration_id", r.MigrationID))
+	}
+	fields = appendTraceFields(fields, r)
+	fields = appendRetryFields(fields, r)
+	if r.Cancelled {
+		fields = append(fields, zap.Bool("sql.cancelled", true))
+	}
+	return append(fields, r.Fields...)
+}
+
+func appendTraceFields(fields []zapcore.Field, r Record) []zapcore.Field {
+	if r.TraceID != "" {
+		fields = append(fields, zap.String("sql.trace_id", r.TraceID))
+	}
+	if r.SpanID != "" {
+		fields = append(fields, zap.String("sql.span_id", r.SpanID))
+	}
+	return fields
+}
+
+func appendRetryFields(fields []zapcore.Field, r Record) []zapcore.Field {
+	if r.Attempt > 0 {
+		fields = append(fields,
+			zap.Int("sql.retry.attempt", r.Attempt),
+			zap.Duration("sql.retry.backoff", r.Backoff),
+		)
+	}
+	return fields
+}
+
+// logArgs encodes SQL bind values as zap array, keeping their types where
+// possible. Values are redacted according to the formatter limits.
+type logArgs struct {
+	args []interface{}
+	f    valueFormatter
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a logArgs) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range a.args {
+		a.f.appendArg(enc, v)
+	}
+	return nil
+}
+
+func (f valueFormatter) appendArg(enc zapcore.ArrayEncoder, value interface{}) {
+	indirectValue := reflect.Indirect(reflect.ValueOf(value))
+	if !indirectValue.IsValid() {
+		enc.AppendReflected(nil)
+		return
+	}
+
+	switch v := indirectValue.Interface().(type) {
+	case string:
+		enc.AppendString(f.redactArg(v))
+	case []byte:
+		if !f.isTextBytes(v) {
+			enc.AppendString("<binary>")
+			return
+		}
+		if f.maxLen > 0 && len(v) > f.maxLen {
+			enc.AppendString("<redacted>")
+			return
+		}
+		s := string(v)
+		if f.bytes == BytesUTF8Escaped {
+			s = escapeControl(s)
 		}
+		enc.AppendString(f.redactArg(s))
+	case bool:
+		enc.AppendBool(v)
+	case int:
+		enc.AppendInt(v)
+	case int8:
+		enc.AppendInt8(v)
+	case int16:
+		enc.AppendInt16(v)
+	case int32:
+		enc.AppendInt32(v)
+	case int64:
+		enc.AppendInt64(v)
+	case uint:
+		enc.AppendUint(v)
+	case uint8:
+		enc.AppendUint8(v)
+	case uint16:
+		enc.AppendUint16(v)
+	case uint32:
+		enc.AppendUint32(v)
+	case uint64:
+		enc.AppendUint64(v)
+	case float32:
+		enc.AppendFloat32(v)
+	case float64:
+		enc.AppendFloat64(v)
+	case time.Time:
+		enc.AppendTime(v)
+	case sql.NamedArg:
+		f.appendArg(enc, v.Value)
+	case driver.Valuer:
+		if dv, err := driverValue(v); err == nil && dv != nil {
+			f.appendArg(enc, dv)
+			return
+		}
+		enc.AppendReflected(nil)
+	default:
+		enc.AppendString(f.redactArg(fmt.Sprintf("%v", v)))
 	}
+}
 
-	return []zapcore.Field{zap.String("sql.source", r.Source)}
+// redactArg returns s, or "<redacted>" if it is longer than the maximum value
+// length.
+func (f valueFormatter) redactArg(s string) string {
+	if f.maxLen > 0 && len(s) > f.maxLen {
+		return "<redacted>"
+	}
+	return s
 }
@@ -0,0 +1,40 @@
+package gormzap
+
+import (
+	"context"
+	"time"
+)
+
+// retry describes an attempt of a retried operation.
+type retry struct {
+	attempt int
+	backoff time.Duration
+}
+
+type retryKey struct{}
+
+// NewContextWithRetry returns a copy of parent which marks queries made
+// within it as a retry of a failed operation, so that retries are
+// distinguishable from fresh traffic in logs. attempt is the number of the
+// retry, starting from 1, and backoff is the delay made before it. Records of
+// ContextLogger bound to the returned context have "sql.retry.attempt" and
+// "sql.retry.backoff" fields.
+//
+// Example usage:
+//  for attempt := 0; ; attempt++ {
+//      ctx := gormzap.NewContextWithRetry(ctx, attempt, backoff)
+//      db := orm.New()
+//      db.SetLogger(log.WithContext(ctx))
+//      ...
+//  }
+//
+// Zero attempt marks the original operation, which is not a retry.
+func NewContextWithRetry(parent context.Context, attempt int, backoff time.Duration) context.Context {
+	return context.WithValue(parent, retryKey{}, retry{attempt: attempt, backoff: backoff})
+}
+
+// contextRetry returns the retry ctx is marked with.
+func contextRetry(ctx context.Context) (retry, bool) {
+	r, ok := ctx.Value(retryKey{}).(retry)
+	return r, ok && r.attempt > 0
+}
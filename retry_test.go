@@ -0,0 +1,34 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestNewContextWithRetry(t *testing.T) {
+	l, buf := logger()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		ctx := gormzap.NewContextWithRetry(context.Background(), attempt, time.Millisecond*100)
+		cl := l.WithContext(ctx)
+		cl.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+		cl.Print("/some/file.go:35", errors.New("some serious error!"))
+	}
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms"}`,
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:35"}`,
+		`{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.retry.attempt":1,"sql.retry.backoff":"100ms","sql.seq":1,"sql.db_time":"5ms"}`,
+		`{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:35","sql.retry.attempt":1,"sql.retry.backoff":"100ms"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithAdaptiveSampling returns Logger option that samples query records
+// depending on throughput: up to qps records per second are all logged, and
+// above it they are sampled progressively, i.e. the n-th record of a second
+// is logged with 1/ceil(n/qps) rate. Hence low-traffic services keep full logs,
+// while the number of records of hot paths grows only logarithmically.
+//
+// Sampled records have "sql.sample_rate" field. Records with warn or higher
+// level are never dropped, and sinks still receive every record. If qps is
+// zero or negative, records are not sampled.
+func WithAdaptiveSampling(qps int) LoggerOption {
+	return func(l *Logger) {
+		if qps <= 0 {
+			l.sampler = nil
+			return
+		}
+		l.sampler = &sampler{qps: int64(qps)}
+	}
+}
+
+// sampler counts query records within one-second windows. Records are
+// counted with atomics, and the lock is only taken to switch windows, like
+// in errorRate.
+type sampler struct {
+	// start is the start of the current window in Unix nanoseconds.
+	start int64
+	count int64
+	qps   int64
+
+	mu sync.Mutex
+}
+
+// keep reports whether the n-th record of the window should be logged, and
+// the rate it is sampled with.
+func (s *sampler) keep(now time.Time) (ok bool, every int64) {
+	if t := now.UnixNano(); t-atomic.LoadInt64(&s.start) >= int64(time.Second) {
+		s.rotate(t)
+	}
+	n := atomic.AddInt64(&s.count, 1)
+
+	if n <= s.qps {
+		return true, 1
+	}
+	every = (n-1)/s.qps + 1
+	return n%every == 0, every
+}
+
+// rotate starts a new window at t, unless a concurrent call has already done
+// so.
+func (s *sampler) rotate(t int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t-atomic.LoadInt64(&s.start) >= int64(time.Second) {
+		atomic.StoreInt64(&s.count, 0)
+		atomic.StoreInt64(&s.start, t)
+	}
+}
+
+// sample reports whether the record should be logged, and adds its sample
+// rate if it is sampled.
+func (l *Logger) sample(rec *Record) bool {
+	if l.sampler == nil || rec.SQL == "" || rec.Level >= zapcore.WarnLevel {
+		return true
+	}
+
+	ok, every := l.sampler.keep(time.Now())
+	if ok && every > 1 {
+		rec.Fields = append(rec.Fields, zap.Float64("sql.sample_rate", 1/float64(every)))
+	}
+	return ok
+}
@@ -0,0 +1,41 @@
+package gormzap_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithAdaptiveSampling(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithAdaptiveSampling(2),
+		gormzap.WithSlowThreshold(time.Second),
+	)
+
+	for i := 0; i < 8; i++ {
+		l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	}
+	l.Print("sql", "/some/file.go:35", time.Second*2, "SELECT 2", []interface{}{}, int64(1))
+
+	// Records 1-2 are logged, then every 2nd of 3-4, every 3rd of 5-6, and
+	// every 4th of 7-8. Slow query is not sampled.
+	expected := []string{
+		`"sql.rows_affected":1}`,
+		`"sql.rows_affected":1}`,
+		`"sql.sample_rate":0.5}`,
+		`"sql.sample_rate":0.3333333333333333}`,
+		`"sql.sample_rate":0.25}`,
+		`"sql.slow":true}`,
+	}
+	lines := buf.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines but got %d: %v", len(expected), len(lines), lines)
+	}
+	for i, e := range expected {
+		if !strings.HasSuffix(lines[i], e) {
+			t.Fatalf("Expected %s to end with %s", lines[i], e)
+		}
+	}
+}
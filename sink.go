@@ -0,0 +1,28 @@
+package gormzap
+
+// RecordSink receives records produced by the logger, e.g. to collect
+// metrics or export them elsewhere. Sinks receive every record after all
+// options are applied, regardless of whether its level is enabled in zap.
+//
+// WriteRecord is called synchronously on the query goroutine, and may be
+// called concurrently, so implementations must be fast and safe for
+// concurrent use.
+type RecordSink interface {
+	WriteRecord(r Record)
+}
+
+// RecordSinkFunc is an adapter to allow the use of ordinary functions as
+// record sinks.
+type RecordSinkFunc func(r Record)
+
+// WriteRecord implements RecordSink.
+func (f RecordSinkFunc) WriteRecord(r Record) {
+	f(r)
+}
+
+// WithSinks returns Logger option that adds sinks receiving every record.
+func WithSinks(sinks ...RecordSink) LoggerOption {
+	return func(l *Logger) {
+		l.sinks = append(l.sinks, sinks...)
+	}
+}
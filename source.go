@@ -0,0 +1,67 @@
+package gormzap
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSourceAsCaller returns Logger option that logs the file:line gorm
+// provides as the zap entry caller instead of "sql.source" field, so that
+// encoders configured to render the caller do not duplicate it. Note that
+// the caller is only rendered if the zap encoder config has CallerKey set.
+// Records with a source that is not in file:line form keep the field.
+func WithSourceAsCaller() LoggerOption {
+	return func(l *Logger) {
+		l.sourceAsCaller = true
+	}
+}
+
+// WithoutSource returns Logger option that neither resolves nor logs the
+// source of records, for deployments which consider file paths sensitive or
+// just noisy. Record Source is left empty, and "sql.source" field is dropped.
+func WithoutSource() LoggerOption {
+	return func(l *Logger) {
+		l.withoutSource = true
+	}
+}
+
+// SourceEnabled reports whether the logger logs the source of records, so
+// that loggers of queries made without gorm can skip resolving it.
+func (l *Logger) SourceEnabled() bool {
+	return !l.load().withoutSource
+}
+
+// source returns the source of the record from value v logged by gorm.
+func (l *Logger) source(v interface{}) string {
+	if l.withoutSource {
+		return ""
+	}
+	return sprint(v)
+}
+
+// sourceCaller parses file:line source into zap entry caller.
+func sourceCaller(source string) (zapcore.EntryCaller, bool) {
+	i := strings.LastIndexByte(source, ':')
+	if i <= 0 {
+		return zapcore.EntryCaller{}, false
+	}
+	line, err := strconv.Atoi(source[i+1:])
+	if err != nil {
+		return zapcore.EntryCaller{}, false
+	}
+	return zapcore.EntryCaller{Defined: true, File: source[:i], Line: line}, true
+}
+
+// dropField removes fields with the key. Fields are modified in place.
+func dropField(fields []zapcore.Field, key string) []zapcore.Field {
+	n := 0
+	for _, f := range fields {
+		if f.Key != key {
+			fields[n] = f
+			n++
+		}
+	}
+	return fields[:n]
+}
@@ -0,0 +1,68 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithSourceAsCaller(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	encoderCfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		CallerKey:      "caller",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), buf, zapcore.DebugLevel)
+	l := gormzap.New(zap.New(core), gormzap.WithSourceAsCaller())
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+	l.Print("log", "unknown", "some message")
+
+	expected := []string{
+		`{"level":"debug","caller":"/some/file.go:34","msg":"gorm query","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1}`,
+		`{"level":"error","caller":"/some/file.go:35","msg":"some serious error!"}`,
+		`{"level":"debug","msg":"some message","sql.source":"unknown"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
+
+func TestWithoutSource(t *testing.T) {
+	l, buf := logger(gormzap.WithoutSource())
+
+	l.Print("sql", "/some/file.go:34", time.Millisecond*5, "SELECT 1", []interface{}{}, int64(1))
+	l.Print("/some/file.go:35", errors.New("some serious error!"))
+	l.LogQuery(context.Background(), "SELECT 2", nil, time.Millisecond*5, 1, nil)
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1}`,
+		`{"level":"error","msg":"some serious error!"}`,
+		`{"level":"debug","msg":"gorm query","sql.duration":"5ms","sql.query":"SELECT 2","sql.rows_affected":1}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+
+	if l.SourceEnabled() {
+		t.Fatalf("Expected source to be disabled")
+	}
+}
@@ -0,0 +1,54 @@
+package gormzap
+
+import "go.uber.org/zap/zapcore"
+
+// Syslog severities as defined by RFC 5424.
+const (
+	SyslogEmergency = 0
+	SyslogAlert     = 1
+	SyslogCritical  = 2
+	SyslogError     = 3
+	SyslogWarning   = 4
+	SyslogNotice    = 5
+	SyslogInfo      = 6
+	SyslogDebug     = 7
+)
+
+// SyslogSeverity returns RFC 5424 severity matching zap level.
+func SyslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return SyslogDebug
+	case zapcore.InfoLevel:
+		return SyslogInfo
+	case zapcore.WarnLevel:
+		return SyslogWarning
+	case zapcore.ErrorLevel:
+		return SyslogError
+	case zapcore.DPanicLevel:
+		return SyslogCritical
+	case zapcore.PanicLevel:
+		return SyslogAlert
+	case zapcore.FatalLevel:
+		return SyslogEmergency
+	}
+	if level < zapcore.DebugLevel {
+		return SyslogDebug
+	}
+	return SyslogEmergency
+}
+
+// SyslogLevelEncoder is zap level encoder that encodes level as numeric
+// RFC 5424 severity. It can be set as EncodeLevel of zap encoder config to
+// log severities instead of zap levels.
+func SyslogLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt(SyslogSeverity(level))
+}
+
+// WithSyslogSeverity returns Logger option that adds numeric RFC 5424
+// severity of the record as "syslog.severity" field, alongside zap level.
+func WithSyslogSeverity() LoggerOption {
+	return func(l *Logger) {
+		l.syslogSeverity = true
+	}
+}
@@ -0,0 +1,42 @@
+package gormzap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithSyslogSeverity(t *testing.T) {
+	l, buf := logger(gormzap.WithSyslogSeverity())
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"level":"error","msg":"some serious error!","sql.source":"/some/file.go:32","syslog.severity":3}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
+
+func TestSyslogLevelEncoder(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	encoderCfg := zapcore.EncoderConfig{
+		MessageKey:  "msg",
+		LevelKey:    "severity",
+		EncodeLevel: gormzap.SyslogLevelEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), buf, zap.DebugLevel)
+	l := gormzap.NewWithCore(core)
+
+	l.Print("/some/file.go:32", errors.New("some serious error!"))
+	expected := `{"severity":3,"msg":"some serious error!","sql.source":"/some/file.go:32"}`
+
+	actual := buf.Lines()[0]
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
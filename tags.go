@@ -0,0 +1,82 @@
+package gormzap
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Tag is a key-value pair parsed from a SQL comment.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Tags is a list of tags in order of appearance in the query.
+type Tags []Tag
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (t Tags) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, tag := range t {
+		enc.AddString(tag.Key, tag.Value)
+	}
+	return nil
+}
+
+// WithCommentTags returns Logger option that parses comments of the query
+// in the form of `-- key=value` or `/* key:value */` into record tags, which
+// are logged as "sql.tags" object. A comment can contain several tags
+// separated by commas, e.g. `/* job=cleanup,endpoint='/users' */`.
+//
+// This can be used to tag specific queries at the call site, e.g. with a job
+// name or an endpoint.
+func WithCommentTags() LoggerOption {
+	return func(l *Logger) {
+		l.commentTags = true
+	}
+}
+
+func parseCommentTags(sql string) Tags {
+	var tags Tags
+	lexSQL(sql, func(kind tokenKind, tok string) {
+		if kind != tokenComment {
+			return
+		}
+
+		if strings.HasPrefix(tok, "--") {
+			tok = tok[2:]
+		} else {
+			tok = strings.TrimSuffix(tok[2:], "*/")
+		}
+
+		for _, pair := range strings.Split(tok, ",") {
+			if tag, ok := parseTag(pair); ok {
+				tags = append(tags, tag)
+			}
+		}
+	})
+	return tags
+}
+
+func parseTag(s string) (Tag, bool) {
+	i := strings.IndexAny(s, "=:")
+	if i == -1 {
+		return Tag{}, false
+	}
+
+	key := strings.TrimSpace(s[:i])
+	if key == "" || strings.IndexFunc(key, isNotTagKeyRune) != -1 {
+		return Tag{}, false
+	}
+
+	value := strings.TrimSpace(s[i+1:])
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return Tag{Key: key, Value: value}, true
+}
+
+func isNotTagKeyRune(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.')
+}
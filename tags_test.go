@@ -0,0 +1,48 @@
+package gormzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWithCommentTags(t *testing.T) {
+	testCases := []struct {
+		sql      string
+		expected string
+	}{
+		{
+			sql:      "SELECT id FROM test -- job=cleanup",
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id FROM test -- job=cleanup","sql.rows_affected":1,"sql.tags":{"job":"cleanup"}}`,
+		},
+		{
+			sql:      "/* endpoint:'/users', user_id: 42 */ SELECT id FROM test",
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"/* endpoint:'/users', user_id: 42 */ SELECT id FROM test","sql.rows_affected":1,"sql.tags":{"endpoint":"/users","user_id":"42"}}`,
+		},
+		{
+			sql:      "SELECT id FROM test WHERE name = '-- job=fake' -- just a note",
+			expected: `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT id FROM test WHERE name = '-- job=fake' -- just a note","sql.rows_affected":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			l, buf := logger(gormzap.WithCommentTags())
+
+			l.Print(
+				"sql",
+				"/some/file.go:34",
+				time.Millisecond*5,
+				tc.sql,
+				[]interface{}{},
+				int64(1),
+			)
+
+			actual := buf.Lines()[0]
+			if actual != tc.expected {
+				t.Fatalf("Expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package gormzap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxTailRecords is the maximum number of records buffered by TailLogger.
+// Records beyond it are dropped and counted.
+const maxTailRecords = 1000
+
+// TailLogger is a gorm logger bound to a request context, which buffers its
+// records and logs them only if the request turns out to be interesting: it
+// ends with an error, any of its records is an error or exceeds the context
+// budget, or it takes longer than the latency budget. Otherwise, only a
+// compact summary is logged. This gives error-triggered verbosity without
+// constant noise. Records that are not logged are not passed to sinks either.
+//
+// Example usage:
+//  t := log.StartTail(ctx, 500*time.Millisecond)
+//  db := orm.New()
+//  db.SetLogger(t)
+//  err := handle(db)
+//  t.End(err)
+type TailLogger struct {
+	ctx     *ContextLogger
+	start   time.Time
+	latency time.Duration
+
+	mu      sync.Mutex
+	records []Record
+	dropped int64
+	errors  int64
+	flush   bool
+}
+
+// StartTail returns a logger buffering records of the request bound to ctx,
+// see TailLogger. If latency is zero or negative, request duration does not
+// cause the records to be logged.
+func (l *Logger) StartTail(ctx context.Context, latency time.Duration) *TailLogger {
+	return &TailLogger{
+		ctx:     l.WithContext(ctx),
+		start:   time.Now(),
+		latency: latency,
+	}
+}
+
+// Print implements gorm's logger interface.
+func (t *TailLogger) Print(values ...interface{}) {
+	rec := t.ctx.newRecord(t.ctx.logger.load(), values...)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if rec.Level >= zapcore.ErrorLevel {
+		t.errors++
+		t.flush = true
+	}
+	if rec.BudgetExceeded {
+		t.flush = true
+	}
+	if len(t.records) >= maxTailRecords {
+		t.dropped++
+		return
+	}
+	t.records = append(t.records, rec)
+}
+
+// End ends the request with err, which may be nil. It logs the buffered
+// records if the request is interesting, and then the request summary:
+// number of queries and errors, total time spent in the database, elapsed
+// time, and whether the records were logged.
+func (t *TailLogger) End(err error) {
+	elapsed := time.Since(t.start)
+	l := t.ctx.logger.load()
+
+	t.mu.Lock()
+	records, dropped, errors := t.records, t.dropped, t.errors
+	flush := t.flush || err != nil || (t.latency > 0 && elapsed > t.latency)
+	t.records, t.dropped = nil, 0
+	t.mu.Unlock()
+
+	if flush {
+		for _, rec := range records {
+			l.log(rec)
+		}
+	}
+
+	queries, dbTime := t.ctx.stats.load()
+	fields := []zapcore.Field{
+		zap.Duration("sql.request.elapsed", elapsed),
+		zap.Int64("sql.request.queries", queries),
+		zap.Duration("sql.request.db_time", dbTime),
+		zap.Int64("sql.request.errors", errors),
+		zap.Bool("sql.request.flushed", flush),
+	}
+	if dropped > 0 {
+		fields = append(fields, zap.Int64("sql.request.dropped", dropped))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	rec := Record{
+		Message: "gorm request finished",
+		Level:   l.level,
+		Fields:  fields,
+	}
+	if flush {
+		escalate(&rec, zapcore.WarnLevel)
+	}
+	l.log(rec)
+}
@@ -0,0 +1,93 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_StartTail(t *testing.T) {
+	query := func(p interface{ Print(...interface{}) }, d time.Duration) {
+		p.Print("sql", "/some/file.go:34", d, "SELECT 1", []interface{}{}, int64(1))
+	}
+
+	t.Run("summary only", func(t *testing.T) {
+		l, buf := logger()
+
+		tl := l.StartTail(context.Background(), time.Minute)
+		query(tl, time.Millisecond*5)
+		query(tl, time.Millisecond*5)
+		tl.End(nil)
+
+		lines := buf.Lines()
+		if len(lines) != 1 {
+			t.Fatalf("Expected 1 line but got %d: %v", len(lines), lines)
+		}
+		for _, e := range []string{
+			`"level":"debug"`,
+			`"msg":"gorm request finished"`,
+			`"sql.request.queries":2`,
+			`"sql.request.db_time":"10ms"`,
+			`"sql.request.errors":0`,
+			`"sql.request.flushed":false`,
+		} {
+			if !strings.Contains(lines[0], e) {
+				t.Fatalf("Expected %s to contain %s", lines[0], e)
+			}
+		}
+	})
+
+	t.Run("flush on error", func(t *testing.T) {
+		l, buf := logger()
+
+		tl := l.StartTail(context.Background(), time.Minute)
+		query(tl, time.Millisecond*5)
+		tl.End(errors.New("request failed"))
+
+		lines := buf.Lines()
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines but got %d: %v", len(lines), lines)
+		}
+		expected := `{"level":"debug","msg":"gorm query","sql.source":"/some/file.go:34","sql.duration":"5ms","sql.query":"SELECT 1","sql.rows_affected":1,"sql.seq":1,"sql.db_time":"5ms"}`
+		if lines[0] != expected {
+			t.Fatalf("Expected %s but got %s", expected, lines[0])
+		}
+		for _, e := range []string{
+			`"level":"warn"`,
+			`"sql.request.flushed":true`,
+			`"error":"request failed"`,
+		} {
+			if !strings.Contains(lines[1], e) {
+				t.Fatalf("Expected %s to contain %s", lines[1], e)
+			}
+		}
+	})
+
+	t.Run("flush on error record", func(t *testing.T) {
+		l, buf := logger()
+
+		tl := l.StartTail(context.Background(), 0)
+		query(tl, time.Millisecond*5)
+		tl.Print("/some/file.go:32", errors.New("some serious error!"))
+		tl.End(nil)
+
+		if lines := buf.Lines(); len(lines) != 3 {
+			t.Fatalf("Expected 3 lines but got %d: %v", len(lines), lines)
+		}
+	})
+
+	t.Run("flush on latency", func(t *testing.T) {
+		l, buf := logger()
+
+		tl := l.StartTail(context.Background(), time.Nanosecond)
+		query(tl, time.Millisecond*5)
+		time.Sleep(time.Millisecond)
+		tl.End(nil)
+
+		if lines := buf.Lines(); len(lines) != 2 {
+			t.Fatalf("Expected 2 lines but got %d: %v", len(lines), lines)
+		}
+	})
+}
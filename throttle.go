@@ -0,0 +1,155 @@
+package gormzap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxThrottledErrors is the maximum number of distinct errors tracked by the
+// error throttle. Errors beyond it are not throttled.
+const maxThrottledErrors = 1024
+
+// WithErrorThrottle returns Logger option that limits the number of logged
+// error records of the same kind to first per interval, e.g. when the
+// database goes down and every query fails the same way. Errors are of the
+// same kind if their messages differ only in literals, e.g. IDs or
+// addresses. When the interval ends, a record with the number of suppressed
+// errors in "sql.suppressed" field is logged, even if no more errors of the
+// kind follow.
+//
+// Sinks still receive every record. If first or interval is zero or
+// negative, errors are not throttled.
+func WithErrorThrottle(first int, interval time.Duration) LoggerOption {
+	return func(l *Logger) {
+		if first <= 0 || interval <= 0 {
+			l.errorThrottle = nil
+			return
+		}
+		l.errorThrottle = &errorThrottle{
+			first:    int64(first),
+			interval: int64(interval),
+		}
+	}
+}
+
+// errorThrottle counts error records by normalized message within fixed
+// intervals. Errors are counted with atomics and entries are kept in
+// sync.Map, so that the throttle does not serialize failing queries. Errors
+// counted concurrently with the end of an interval may be attributed to
+// either one.
+type errorThrottle struct {
+	n        int64
+	first    int64
+	interval int64
+
+	// entries holds *throttleEntry by normalized message.
+	entries sync.Map
+}
+
+type throttleEntry struct {
+	// start is the start of the current interval in Unix nanoseconds.
+	start      int64
+	count      int64
+	suppressed int64
+
+	// message and source are of the first error of the kind, reported with
+	// the number of suppressed errors.
+	message string
+	source  string
+	level   zapcore.Level
+}
+
+// allow reports whether the error record should be logged. The first error
+// suppressed since the last report schedules report to be called with the
+// entry when the current interval ends.
+func (t *errorThrottle) allow(rec Record, now time.Time, report func(e *throttleEntry)) bool {
+	n := now.UnixNano()
+	e := t.entry(rec, n)
+	if e == nil {
+		return true
+	}
+
+	if start := atomic.LoadInt64(&e.start); n-start >= t.interval && atomic.CompareAndSwapInt64(&e.start, start, n) {
+		atomic.StoreInt64(&e.count, 0)
+	}
+
+	if atomic.AddInt64(&e.count, 1) <= t.first {
+		return true
+	}
+	if atomic.AddInt64(&e.suppressed, 1) == 1 {
+		end := time.Unix(0, atomic.LoadInt64(&e.start)+t.interval)
+		time.AfterFunc(end.Sub(now), func() {
+			report(e)
+		})
+	}
+	return false
+}
+
+// entry returns the entry of the record, or nil if its kind is not tracked
+// and the throttle already tracks maxThrottledErrors kinds.
+func (t *errorThrottle) entry(rec Record, now int64) *throttleEntry {
+	key := normalizeSQL(rec.Message)
+	if v, ok := t.entries.Load(key); ok {
+		return v.(*throttleEntry)
+	}
+	if atomic.LoadInt64(&t.n) >= maxThrottledErrors {
+		t.prune(now)
+	}
+	if atomic.AddInt64(&t.n, 1) > maxThrottledErrors {
+		atomic.AddInt64(&t.n, -1)
+		return nil
+	}
+	v, loaded := t.entries.LoadOrStore(key, &throttleEntry{
+		start:   now,
+		message: rec.Message,
+		source:  rec.Source,
+		level:   rec.Level,
+	})
+	if loaded {
+		atomic.AddInt64(&t.n, -1)
+	}
+	return v.(*throttleEntry)
+}
+
+// prune removes entries whose interval has ended and which have no
+// suppressed errors left to report.
+func (t *errorThrottle) prune(now int64) {
+	t.entries.Range(func(key, v interface{}) bool {
+		e := v.(*throttleEntry)
+		if now-atomic.LoadInt64(&e.start) >= t.interval && atomic.LoadInt64(&e.suppressed) == 0 {
+			if _, deleted := t.entries.LoadAndDelete(key); deleted {
+				atomic.AddInt64(&t.n, -1)
+			}
+		}
+		return true
+	})
+}
+
+// throttle reports whether the record should be logged.
+func (l *Logger) throttle(rec Record) bool {
+	if l.errorThrottle == nil || rec.Level < zapcore.ErrorLevel {
+		return true
+	}
+	return l.errorThrottle.allow(rec, time.Now(), l.reportSuppressed)
+}
+
+// reportSuppressed logs the summary of errors suppressed since the last one.
+func (l *Logger) reportSuppressed(e *throttleEntry) {
+	suppressed := atomic.SwapInt64(&e.suppressed, 0)
+	if suppressed == 0 {
+		return
+	}
+	l.write(Record{
+		Message: "gormzap: suppressed similar errors",
+		Source:  e.source,
+		Level:   e.level,
+		Fields: []zapcore.Field{
+			zap.String("sql.error", e.message),
+			zap.Int64("sql.suppressed", suppressed),
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package gormzap_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithErrorThrottle(t *testing.T) {
+	buf := &lockedBuffer{}
+	l := gormzap.New(zapLoggerTo(buf), gormzap.WithErrorThrottle(2, time.Millisecond*50))
+
+	for i := 0; i < 5; i++ {
+		l.Print("/some/file.go:32", fmt.Errorf("dial tcp 10.0.0.%d:5432: connection refused", i+1))
+	}
+	l.Print("/some/file.go:33", errors.New("other error"))
+
+	expected := []string{
+		`{"level":"error","msg":"dial tcp 10.0.0.1:5432: connection refused","sql.source":"/some/file.go:32"}`,
+		`{"level":"error","msg":"dial tcp 10.0.0.2:5432: connection refused","sql.source":"/some/file.go:32"}`,
+		`{"level":"error","msg":"other error","sql.source":"/some/file.go:33"}`,
+		`{"level":"error","msg":"gormzap: suppressed similar errors","sql.source":"/some/file.go:32","sql.error":"dial tcp 10.0.0.1:5432: connection refused","sql.suppressed":3}`,
+	}
+
+	// The summary is logged when the interval ends, without waiting for
+	// another error.
+	deadline := time.Now().Add(time.Second)
+	for len(buf.Lines()) < len(expected) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	l.Print("/some/file.go:32", errors.New("dial tcp 10.0.0.1:5432: connection refused"))
+	expected = append(expected, `{"level":"error","msg":"dial tcp 10.0.0.1:5432: connection refused","sql.source":"/some/file.go:32"}`)
+
+	lines := buf.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines but got %d: %v", len(expected), len(lines), lines)
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}
+
+// lockedBuffer is zaptest.Buffer safe to be written by timers.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf zaptest.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Sync() error {
+	return nil
+}
+
+func (b *lockedBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Lines()
+}
@@ -0,0 +1,53 @@
+package gormzap
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WideEventRecordToFields is an encoder func for gormzap log records that
+// emits a flat, wide set of fields following Honeycomb conventions, one event
+// per query: "duration_ms" as float, "query", "table", "operation", "rows",
+// "source", "slow", and "trace.trace_id" and "trace.span_id" if known. Error
+// records have "error" field with the error message.
+//
+// Unlike other encoders, it does not use "sql." prefix for field keys.
+func WideEventRecordToFields(r Record) []zapcore.Field {
+	var fields []zapcore.Field
+
+	if r.SQL != "" {
+		fields = append(fields,
+			zap.Float64("duration_ms", float64(r.Duration)/float64(time.Millisecond)),
+			zap.String("query", r.SQL),
+			zap.String("table", r.Table()),
+			zap.String("operation", r.Operation()),
+			zap.Int64("rows", r.RowsAffected),
+			zap.String("source", r.Source),
+			zap.Bool("slow", r.Slow),
+		)
+		if r.QueryHash != "" {
+			fields = append(fields, zap.String("query_hash", r.QueryHash))
+		}
+		if r.Seq > 0 {
+			fields = append(fields,
+				zap.Int64("seq", r.Seq),
+				zap.Float64("db_time_ms", float64(r.DBTime)/float64(time.Millisecond)),
+			)
+		}
+	} else {
+		fields = append(fields, zap.String("source", r.Source))
+		if r.Level >= zapcore.ErrorLevel {
+			fields = append(fields, zap.String("error", r.Message))
+		}
+	}
+
+	if r.TraceID != "" {
+		fields = append(fields, zap.String("trace.trace_id", r.TraceID))
+	}
+	if r.SpanID != "" {
+		fields = append(fields, zap.String("trace.span_id", r.SpanID))
+	}
+	return fields
+}
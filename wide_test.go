@@ -0,0 +1,41 @@
+package gormzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/gormzap"
+)
+
+func TestWideEventRecordToFields(t *testing.T) {
+	l, buf := logger(
+		gormzap.WithRecordToFields(gormzap.WideEventRecordToFields),
+		gormzap.WithTraceIDs(func(ctx context.Context) (string, string) {
+			return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+		}),
+	)
+	cl := l.WithContext(context.Background())
+
+	cl.Print(
+		"sql",
+		"/some/file.go:34",
+		time.Microsecond*1500,
+		"UPDATE users SET name = $1 WHERE id = $2",
+		[]interface{}{"foo", 42},
+		int64(1),
+	)
+	cl.Print("/some/file.go:35", errors.New("some serious error!"))
+
+	expected := []string{
+		`{"level":"debug","msg":"gorm query","duration_ms":1.5,"query":"UPDATE users SET name = 'foo' WHERE id = 42","table":"users","operation":"UPDATE","rows":1,"source":"/some/file.go:34","slow":false,"seq":1,"db_time_ms":1.5,"trace.trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","trace.span_id":"00f067aa0ba902b7"}`,
+		`{"level":"error","msg":"some serious error!","source":"/some/file.go:35","error":"some serious error!","trace.trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","trace.span_id":"00f067aa0ba902b7"}`,
+	}
+	lines := buf.Lines()
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Fatalf("Expected %s but got %s", e, lines[i])
+		}
+	}
+}